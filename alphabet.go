@@ -0,0 +1,186 @@
+package main
+
+import (
+	"fmt"
+	"os"
+	"strings"
+	"unicode"
+	"unicode/utf8"
+
+	"github.com/erikbryant/dictionaries"
+)
+
+// alphabet describes the letters a language's words are made of, where to
+// find its dictionary, and how raw words are normalized before use
+type alphabet struct {
+	letters string
+	dict    string
+	guesses string // more words that may be guessed but are never the answer, if any
+	common  string // words ordered most common first, if the language has them
+	lower   bool   // convert letters to lowercase
+	fold    bool   // fold accented letters that are not in letters to their base letter
+	strip   bool   // remove anything that is not a letter
+}
+
+// alphabets maps language names to their alphabets. Clones differ on whether
+// accented letters are tiles of their own, hence the variants. Only English
+// has a dictionary of its own; the others need one given with -dict.
+var alphabets = map[string]alphabet{
+	"en":         {letters: "abcdefghijklmnopqrstuvwxyz", dict: "../dictionaries/merged.dict", common: "../dictionaries/en_frequency.dict", lower: true, fold: true, strip: true},
+	"es":         {letters: "abcdefghijklmnñopqrstuvwxyz", lower: true, fold: true, strip: true},
+	"es-accents": {letters: "aábcdeéfghiíjklmnñoópqrstuúüvwxyz", lower: true, fold: true, strip: true},
+	"de":         {letters: "abcdefghijklmnopqrstuvwxyzäöüß", lower: true, fold: true, strip: true},
+	"de-folded":  {letters: "abcdefghijklmnopqrstuvwxyzß", lower: true, fold: true, strip: true},
+}
+
+// latinFold maps accented Latin letters to their unaccented base letter
+var latinFold = map[rune]rune{
+	'à': 'a', 'á': 'a', 'â': 'a', 'ã': 'a', 'ä': 'a', 'å': 'a',
+	'ç': 'c',
+	'è': 'e', 'é': 'e', 'ê': 'e', 'ë': 'e',
+	'ì': 'i', 'í': 'i', 'î': 'i', 'ï': 'i',
+	'ñ': 'n',
+	'ò': 'o', 'ó': 'o', 'ô': 'o', 'õ': 'o', 'ö': 'o',
+	'ù': 'u', 'ú': 'u', 'û': 'u', 'ü': 'u',
+	'ý': 'y', 'ÿ': 'y',
+}
+
+// normalize returns word cleaned up according to the alphabet's rules
+func (a alphabet) normalize(word string) string {
+	out := []rune{}
+
+	for _, r := range word {
+		if a.lower {
+			r = unicode.ToLower(r)
+		}
+		if a.fold && !strings.ContainsRune(a.letters, r) {
+			if base, ok := latinFold[r]; ok {
+				r = base
+			}
+		}
+		if a.strip && !unicode.IsLetter(r) {
+			continue
+		}
+		out = append(out, r)
+	}
+
+	return string(out)
+}
+
+// valid returns true if every letter of word is in the alphabet
+func (a alphabet) valid(word string) bool {
+	for _, r := range word {
+		if !strings.ContainsRune(a.letters, r) {
+			return false
+		}
+	}
+
+	return true
+}
+
+// filter returns the words that have wordLen letters, all from the alphabet
+func (a alphabet) filter(words []string, wordLen int) []string {
+	matches := []string{}
+
+	for _, word := range words {
+		if utf8.RuneCountInString(word) == wordLen && a.valid(word) {
+			matches = append(matches, word)
+		}
+	}
+
+	return matches
+}
+
+// load returns the normalized words of a dictionary file, one per line. A
+// file that cannot be read is an error rather than an empty list.
+func (a alphabet) load(file string) ([]string, error) {
+	raw, err := os.ReadFile(file)
+	if err != nil {
+		return nil, err
+	}
+
+	words := strings.Split(string(raw), "\n")
+	for i := range words {
+		words[i] = a.normalize(words[i])
+	}
+
+	return words, nil
+}
+
+// words returns the normalized words of the alphabet's dictionary
+func (a alphabet) words() ([]string, error) {
+	if a.dict == "" {
+		return nil, fmt.Errorf("no dictionary for the alphabet %s, give one with -dict", a.letters)
+	}
+
+	return a.load(a.dict)
+}
+
+// commonWords returns the n most common words that have wordLen letters
+func (a alphabet) commonWords(wordLen, n int) ([]string, error) {
+	if a.common == "" {
+		return nil, fmt.Errorf("no list of common words for the alphabet %s", a.letters)
+	}
+
+	words, err := a.load(a.common)
+	if err != nil {
+		return nil, err
+	}
+
+	common := []string{}
+	seen := map[string]bool{}
+	for _, word := range a.filter(words, wordLen) {
+		if len(common) == n {
+			break
+		}
+		if !seen[word] {
+			seen[word] = true
+			common = append(common, word)
+		}
+	}
+	if len(common) == 0 {
+		return nil, fmt.Errorf("no common words of length %d", wordLen)
+	}
+
+	return common, nil
+}
+
+// letterFrequency returns the frequency of letters in the given words overall
+// and by letter position
+func letterFrequency(words []string) (map[rune]int, []map[rune]int) {
+	if len(words) == 0 {
+		return nil, nil
+	}
+
+	lFreq := map[rune]int{}
+	lByPos := make([]map[rune]int, utf8.RuneCountInString(words[0]))
+	for i := range lByPos {
+		lByPos[i] = map[rune]int{}
+	}
+
+	for _, word := range words {
+		for i, r := range []rune(word) {
+			if i >= len(lByPos) {
+				break
+			}
+			lByPos[i][r]++
+			lFreq[r]++
+		}
+	}
+
+	return lFreq, lByPos
+}
+
+// prettyPrintFreq returns a formatted string representation of the given frequencies
+func prettyPrintFreq(f map[rune]int) string {
+	out := []string{}
+
+	for key, val := range f {
+		if val == 0 {
+			continue
+		}
+		out = append(out, fmt.Sprintf("%c:%3d", key, val))
+	}
+
+	return fmt.Sprintf("  %s\n", strings.Join(dictionaries.SortUnique(out), " "))
+}
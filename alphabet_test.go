@@ -0,0 +1,179 @@
+package main
+
+import (
+	"os"
+	"path/filepath"
+	"testing"
+)
+
+func equalFreq(a, b map[rune]int) bool {
+	if len(a) != len(b) {
+		return false
+	}
+
+	for key := range a {
+		if a[key] != b[key] {
+			return false
+		}
+	}
+
+	return true
+}
+
+func TestAlphabetValid(t *testing.T) {
+	testCases := []struct {
+		lang     string
+		w        string
+		expected bool
+	}{
+		{"en", "", true},
+		{"en", "crane", true},
+		{"en", "niño", false},
+		{"en", "Crane", false},
+		{"es", "niño", true},
+		{"es", "größe", false},
+		{"de", "größe", true},
+		{"de", "straße", true},
+		{"de", "niño", false},
+	}
+
+	for _, testCase := range testCases {
+		answer := alphabets[testCase.lang].valid(testCase.w)
+		if answer != testCase.expected {
+			t.Errorf("ERROR: For %s %s expected %t, got %t", testCase.lang, testCase.w, testCase.expected, answer)
+		}
+	}
+}
+
+func TestAlphabetFilter(t *testing.T) {
+	testCases := []struct {
+		lang     string
+		words    []string
+		l        int
+		expected []string
+	}{
+		{"en", []string{}, 4, []string{}},
+		{"en", []string{"nino", "niño", "no"}, 4, []string{"nino"}},
+		{"es", []string{"nino", "niño", "no"}, 4, []string{"nino", "niño"}},
+		{"es", []string{"nino", "niño", "no"}, 2, []string{"no"}},
+		{"de", []string{"größe", "grosse", "groß"}, 5, []string{"größe"}},
+		{"de", []string{"größe", "grosse", "groß"}, 4, []string{"groß"}},
+	}
+
+	for _, testCase := range testCases {
+		answer := alphabets[testCase.lang].filter(testCase.words, testCase.l)
+		if !equal(answer, testCase.expected) {
+			t.Errorf("ERROR: For %s %v %d expected %v, got %v", testCase.lang, testCase.words, testCase.l, testCase.expected, answer)
+		}
+	}
+}
+
+func TestLetterFrequency(t *testing.T) {
+	testCases := []struct {
+		words    []string
+		expected map[rune]int
+		byPos    []map[rune]int
+	}{
+		{[]string{}, nil, nil},
+		{[]string{"ab"}, map[rune]int{'a': 1, 'b': 1}, []map[rune]int{{'a': 1}, {'b': 1}}},
+		{[]string{"ab", "bb"}, map[rune]int{'a': 1, 'b': 3}, []map[rune]int{{'a': 1, 'b': 1}, {'b': 2}}},
+		{[]string{"ñu", "ño"}, map[rune]int{'ñ': 2, 'u': 1, 'o': 1}, []map[rune]int{{'ñ': 2}, {'u': 1, 'o': 1}}},
+	}
+
+	for _, testCase := range testCases {
+		answer, byPos := letterFrequency(testCase.words)
+		if !equalFreq(answer, testCase.expected) {
+			t.Errorf("ERROR: For %v expected %v, got %v", testCase.words, testCase.expected, answer)
+		}
+		if len(byPos) != len(testCase.byPos) {
+			t.Errorf("ERROR: For %v expected %v, got %v", testCase.words, testCase.byPos, byPos)
+			continue
+		}
+		for i := range byPos {
+			if !equalFreq(byPos[i], testCase.byPos[i]) {
+				t.Errorf("ERROR: For %v expected %v, got %v", testCase.words, testCase.byPos, byPos)
+			}
+		}
+	}
+}
+
+func TestPrettyPrintFreq(t *testing.T) {
+	testCases := []struct {
+		f        map[rune]int
+		expected string
+	}{
+		{map[rune]int{}, "  \n"},
+		{map[rune]int{'b': 2, 'a': 10, 'c': 0}, "  a: 10 b:  2\n"},
+		{map[rune]int{'ñ': 1, 'n': 3}, "  n:  3 ñ:  1\n"},
+	}
+
+	for _, testCase := range testCases {
+		answer := prettyPrintFreq(testCase.f)
+		if answer != testCase.expected {
+			t.Errorf("ERROR: For %v expected '%s', got '%s'", testCase.f, testCase.expected, answer)
+		}
+	}
+}
+
+func TestAlphabetNormalize(t *testing.T) {
+	testCases := []struct {
+		lang     string
+		w        string
+		expected string
+	}{
+		{"en", "", ""},
+		{"en", "Crane", "crane"},
+		{"en", "café", "cafe"},
+		{"en", "rock'n'roll", "rocknroll"},
+		{"en", "Über-cool", "ubercool"},
+		{"es", "Niño", "niño"},
+		{"es", "canción", "cancion"},
+		{"es-accents", "canción", "canción"},
+		{"es-accents", "CANCIÓN", "canción"},
+		{"de", "Größe", "größe"},
+		{"de-folded", "Größe", "große"},
+		{"de", "Straße", "straße"},
+	}
+
+	for _, testCase := range testCases {
+		answer := alphabets[testCase.lang].normalize(testCase.w)
+		if answer != testCase.expected {
+			t.Errorf("ERROR: For %s %s expected %s, got %s", testCase.lang, testCase.w, testCase.expected, answer)
+		}
+	}
+}
+
+func TestCommonWords(t *testing.T) {
+	dir := t.TempDir()
+	file := filepath.Join(dir, "common.dict")
+	os.WriteFile(file, []byte("the\nOf\nand\ncat\nThe\ndogs\nfox\n"), 0644)
+	alpha := alphabet{letters: "abcdefghijklmnopqrstuvwxyz", common: file, lower: true}
+
+	testCases := []struct {
+		wordLen  int
+		n        int
+		expected []string
+	}{
+		{3, 2, []string{"the", "and"}},
+		{3, 10, []string{"the", "and", "cat", "fox"}},
+		{4, 10, []string{"dogs"}},
+	}
+
+	for _, testCase := range testCases {
+		answer, err := alpha.commonWords(testCase.wordLen, testCase.n)
+		if err != nil || !equal(answer, testCase.expected) {
+			t.Errorf("ERROR: For %d %d expected %v, got %v %v", testCase.wordLen, testCase.n, testCase.expected, answer, err)
+		}
+	}
+
+	if _, err := alpha.commonWords(5, 10); err == nil {
+		t.Errorf("ERROR: expected an error with no common words of length 5")
+	}
+	if _, err := alphabets["es"].commonWords(5, 10); err == nil {
+		t.Errorf("ERROR: expected an error for a language with no common words")
+	}
+	alpha.common = filepath.Join(dir, "missing.dict")
+	if _, err := alpha.commonWords(3, 10); err == nil {
+		t.Errorf("ERROR: expected an error for a missing list of common words")
+	}
+}
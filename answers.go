@@ -0,0 +1,104 @@
+package main
+
+import (
+	"bufio"
+	"errors"
+	"fmt"
+	"io/fs"
+	"os"
+	"strings"
+)
+
+// pastAnswer is the mystery word of an earlier puzzle
+type pastAnswer struct {
+	day  string // the date or puzzle number
+	word string
+}
+
+// loadAnswers returns the past answers stored in file, one "<date or number>
+// <word>" per line. Anything after a # is a comment. If the file does not
+// exist there are no past answers.
+func loadAnswers(file string, alpha alphabet) ([]pastAnswer, error) {
+	f, err := os.Open(file)
+	if errors.Is(err, fs.ErrNotExist) {
+		return nil, nil
+	}
+	if err != nil {
+		return nil, err
+	}
+	defer f.Close()
+
+	answers := []pastAnswer{}
+	scanner := bufio.NewScanner(f)
+	for n := 1; scanner.Scan(); n++ {
+		line, _, _ := strings.Cut(scanner.Text(), "#")
+		fields := strings.Fields(line)
+		if len(fields) == 0 {
+			continue
+		}
+		if len(fields) != 2 {
+			return nil, fmt.Errorf("%s line %d: expected <date or number> <word>, got %s", file, n, scanner.Text())
+		}
+		answers = append(answers, pastAnswer{fields[0], alpha.normalize(fields[1])})
+	}
+
+	return answers, scanner.Err()
+}
+
+// excludeAnswers returns the words that have not been an answer before
+func excludeAnswers(words []string, answers []pastAnswer) []string {
+	if len(answers) == 0 {
+		return words
+	}
+
+	used := map[string]bool{}
+	for _, a := range answers {
+		used[a.word] = true
+	}
+
+	remaining := []string{}
+	for _, word := range words {
+		if !used[word] {
+			remaining = append(remaining, word)
+		}
+	}
+
+	return remaining
+}
+
+// appendAnswer adds the answer to the past answers in file
+func appendAnswer(file string, answer pastAnswer, alpha alphabet) error {
+	if answer.word == "" || !alpha.valid(answer.word) {
+		return fmt.Errorf("answer %q contains letters not in the alphabet %s", answer.word, alpha.letters)
+	}
+	// A # would start a comment and a space would split the day in two
+	if answer.day == "" || strings.ContainsAny(answer.day, "# \t") {
+		return fmt.Errorf("day %q must be a date or puzzle number", answer.day)
+	}
+
+	answers, err := loadAnswers(file, alpha)
+	if err != nil {
+		return err
+	}
+	for _, a := range answers {
+		if puzzleKey(a.day) == puzzleKey(answer.day) {
+			return fmt.Errorf("%s already has an answer for %s: %s", file, a.day, a.word)
+		}
+		if a.word == answer.word {
+			return fmt.Errorf("%s was already the answer for %s", a.word, a.day)
+		}
+	}
+
+	f, err := os.OpenFile(file, os.O_APPEND|os.O_CREATE|os.O_WRONLY, 0644)
+	if err != nil {
+		return err
+	}
+
+	_, err = fmt.Fprintf(f, "%s %s\n", answer.day, answer.word)
+	if err != nil {
+		f.Close()
+		return err
+	}
+
+	return f.Close()
+}
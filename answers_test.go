@@ -0,0 +1,89 @@
+package main
+
+import (
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+)
+
+func TestLoadAnswers(t *testing.T) {
+	dir := t.TempDir()
+
+	answers, err := loadAnswers(filepath.Join(dir, "missing"), alphabets["en"])
+	if answers != nil || err != nil {
+		t.Errorf("ERROR: expected no answers for a missing file, got %v %v", answers, err)
+	}
+
+	file := filepath.Join(dir, "past.answers")
+	os.WriteFile(file, []byte("# past answers\n1000 Crane\n\n2024-05-01 moist # a comment\n"), 0644)
+	answers, err = loadAnswers(file, alphabets["en"])
+	expected := []pastAnswer{{"1000", "crane"}, {"2024-05-01", "moist"}}
+	if err != nil || len(answers) != len(expected) || answers[0] != expected[0] || answers[1] != expected[1] {
+		t.Errorf("ERROR: expected %v, got %v %v", expected, answers, err)
+	}
+
+	os.WriteFile(file, []byte("1000 crane\n1001\n"), 0644)
+	_, err = loadAnswers(file, alphabets["en"])
+	if err == nil || !strings.Contains(err.Error(), "line 2") {
+		t.Errorf("ERROR: expected an error on line 2, got %v", err)
+	}
+}
+
+func TestExcludeAnswers(t *testing.T) {
+	testCases := []struct {
+		answers  []pastAnswer
+		expected []string
+	}{
+		{nil, consistencyTestWords},
+		{[]pastAnswer{{"1", "cat"}}, []string{"cot", "dog", "tag", "act"}},
+		{[]pastAnswer{{"1", "cat"}, {"2", "act"}, {"3", "zzz"}}, []string{"cot", "dog", "tag"}},
+	}
+
+	for _, testCase := range testCases {
+		answer := excludeAnswers(consistencyTestWords, testCase.answers)
+		if !equal(answer, testCase.expected) {
+			t.Errorf("ERROR: For %v expected %v, got %v", testCase.answers, testCase.expected, answer)
+		}
+	}
+}
+
+func TestAppendAnswer(t *testing.T) {
+	file := filepath.Join(t.TempDir(), "past.answers")
+	alpha := alphabets["en"]
+
+	testCases := []struct {
+		answer pastAnswer
+		ok     bool
+	}{
+		{pastAnswer{"1000", "crane"}, true},
+		{pastAnswer{"1001", "moist"}, true},
+		{pastAnswer{"1001", "pious"}, false},
+		{pastAnswer{"1002", "crane"}, false},
+		{pastAnswer{"1002", "cr4ne"}, false},
+		{pastAnswer{"1002", ""}, false},
+		// Dates and puzzle numbers name the same days
+		{pastAnswer{"5", "tapir"}, true},
+		{pastAnswer{"2021-06-24", "pious"}, false},
+		// Days that could not be read back
+		{pastAnswer{"#1234", "sting"}, false},
+		{pastAnswer{"12 34", "sting"}, false},
+		{pastAnswer{"", "sting"}, false},
+	}
+
+	for _, testCase := range testCases {
+		err := appendAnswer(file, testCase.answer, alpha)
+		if (err == nil) != testCase.ok {
+			t.Errorf("ERROR: For %v expected ok=%t, got %v", testCase.answer, testCase.ok, err)
+		}
+	}
+
+	answers, err := loadAnswers(file, alpha)
+	if err != nil || len(answers) != 3 || answers[1] != (pastAnswer{"1001", "moist"}) {
+		t.Errorf("ERROR: expected three answers, got %v %v", answers, err)
+	}
+	remaining := excludeAnswers([]string{"crane", "moist", "sting", "tapir"}, answers)
+	if !equal(remaining, []string{"sting"}) {
+		t.Errorf("ERROR: expected the recorded answers to be excluded, got %v", remaining)
+	}
+}
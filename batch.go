@@ -0,0 +1,284 @@
+package main
+
+import (
+	"bufio"
+	"fmt"
+	"io"
+	"strings"
+	"sync"
+
+	"github.com/erikbryant/dictionaries"
+)
+
+// puzzleDay is one day's puzzle read from a batch file
+type puzzleDay struct {
+	id     string // the date or puzzle number
+	answer string // the mystery word, if known
+	input  puzzleInput
+}
+
+// dayResult is what cracking one puzzleDay found
+type dayResult struct {
+	day        puzzleDay
+	candidates []string
+	err        error
+}
+
+// batchStats summarizes the days whose answer is known
+type batchStats struct {
+	days       int     // days with a known answer
+	found      int     // days whose answer was among the candidates
+	unique     int     // days whose answer was the only candidate
+	candidates float64 // mean number of candidates
+}
+
+// wordLists are the mystery and guessable words of one length
+type wordLists struct {
+	mysteries  []string
+	guessables []string
+}
+
+// isPuzzleHeader returns true if the line starts a new puzzle day
+func isPuzzleHeader(line string) bool {
+	fields := strings.Fields(line)
+	return len(fields) > 0 && strings.ToLower(fields[0]) == "puzzle"
+}
+
+// readBatch reads puzzle days. Each day starts with a header line of the form
+// "puzzle <date or number> [answer]" followed by its colorbars and guesses in
+// the same form as -input.
+func readBatch(r io.Reader, alpha alphabet) ([]puzzleDay, error) {
+	days := []puzzleDay{}
+	errs := []string{}
+
+	scanner := bufio.NewScanner(r)
+	for n := 1; scanner.Scan(); n++ {
+		line := scanner.Text()
+
+		if isPuzzleHeader(line) {
+			header, _, _ := strings.Cut(line, "#")
+			fields := strings.Fields(header)
+			if len(fields) < 2 || len(fields) > 3 {
+				errs = append(errs, fmt.Sprintf("line %d: expected puzzle <date or number> [answer], got %s", n, line))
+				continue
+			}
+			day := puzzleDay{id: fields[1], input: newPuzzleInput()}
+			if len(fields) == 3 {
+				day.answer = alpha.normalize(fields[2])
+			}
+			days = append(days, day)
+			continue
+		}
+
+		if len(days) == 0 {
+			if l, _, _ := strings.Cut(line, "#"); strings.TrimSpace(l) != "" {
+				errs = append(errs, fmt.Sprintf("line %d: expected a puzzle header before %s", n, line))
+			}
+			continue
+		}
+
+		if err := days[len(days)-1].input.addLine(line, alpha); err != nil {
+			errs = append(errs, fmt.Sprintf("line %d: %v", n, err))
+		}
+	}
+	if err := scanner.Err(); err != nil {
+		return nil, err
+	}
+
+	if len(errs) > 0 {
+		return nil, fmt.Errorf("%s", strings.Join(errs, "\n"))
+	}
+
+	return days, nil
+}
+
+// dayLength returns the word length of the day's puzzle
+func dayLength(day puzzleDay) (int, error) {
+	length := len([]rune(day.answer))
+	return wordLength(length, day.input.masks(), day.input.guessWords, day.input.guessMasks)
+}
+
+// crackDay returns the words that could be the answer to the day's puzzle
+func crackDay(day puzzleDay, words wordLists) dayResult {
+	masks := day.input.masks()
+	h := newHistory(day.input.guessWords, day.input.guessMasks)
+
+	matches := applyMasks(words.mysteries, words.guessables, masks)
+	if len(matches) == 0 {
+		return dayResult{day, matches, noMatches(words.mysteries, words.guessables, masks)}
+	}
+
+	// The colorbars left some words, so the guesses are what rule them out
+	candidates := consistent(matches, h)
+	if len(candidates) == 0 {
+		return dayResult{day, candidates, checkHistory(matches, h)}
+	}
+
+	return dayResult{day, candidates, nil}
+}
+
+// parallel calls f for each of 0 through n-1, workers at a time
+func parallel(n, workers int, f func(i int)) {
+	jobs := make(chan int)
+	var wg sync.WaitGroup
+
+	for w := 0; w < max(workers, 1); w++ {
+		wg.Add(1)
+		go func() {
+			defer wg.Done()
+			for i := range jobs {
+				f(i)
+			}
+		}()
+	}
+
+	for i := 0; i < n; i++ {
+		jobs <- i
+	}
+	close(jobs)
+	wg.Wait()
+}
+
+// crackDays cracks each of the days, workers at a time, returning the results
+// in the same order as the days
+func crackDays(days []puzzleDay, lists map[int]wordLists, workers int) []dayResult {
+	results := make([]dayResult, len(days))
+
+	parallel(len(days), workers, func(i int) {
+		length, err := dayLength(days[i])
+		if err != nil {
+			results[i] = dayResult{days[i], []string{}, err}
+			return
+		}
+		results[i] = crackDay(days[i], lists[length])
+	})
+
+	return results
+}
+
+// summarize returns the accuracy of the results whose answer is known
+func summarize(results []dayResult) batchStats {
+	stats := batchStats{}
+	total := 0
+
+	for _, r := range results {
+		if r.day.answer == "" {
+			continue
+		}
+		stats.days++
+		total += len(r.candidates)
+		if dictionaries.ContainsWord(r.candidates, r.day.answer) {
+			stats.found++
+			if len(r.candidates) == 1 {
+				stats.unique++
+			}
+		}
+	}
+
+	if stats.days > 0 {
+		stats.candidates = float64(total) / float64(stats.days)
+	}
+
+	return stats
+}
+
+// percent returns n as a percentage of total
+func percent(n, total int) float64 {
+	if total == 0 {
+		return 0
+	}
+
+	return 100 * float64(n) / float64(total)
+}
+
+// printDay prints the candidates found for one day
+func printDay(r dayResult) {
+	if r.err != nil {
+		fmt.Printf("%s: %v\n", r.day.id, r.err)
+		return
+	}
+
+	listed := r.candidates
+	more := ""
+	if len(listed) > 10 {
+		listed = listed[:10]
+		more = " ..."
+	}
+	fmt.Printf("%s: %d candidates %v%s", r.day.id, len(r.candidates), listed, more)
+
+	if r.day.answer != "" {
+		if dictionaries.ContainsWord(r.candidates, r.day.answer) {
+			fmt.Printf(" answer %s found", r.day.answer)
+		} else {
+			fmt.Printf(" answer %s MISSED", r.day.answer)
+		}
+	}
+	fmt.Println()
+}
+
+// loadBatch returns the puzzle days in file, with any answers from the
+// schedule filled in, and the words of each of their lengths
+func loadBatch(file string, alpha alphabet, s schedule) ([]puzzleDay, map[int]wordLists, error) {
+	f, err := openInput(file)
+	if err != nil {
+		return nil, nil, err
+	}
+	defer f.Close()
+
+	days, err := readBatch(f, alpha)
+	if err == nil {
+		err = scheduleDays(days, s)
+	}
+	if err != nil {
+		return nil, nil, err
+	}
+
+	// Load the dictionaries up front so the workers can share them
+	lists := map[int]wordLists{}
+	for _, day := range days {
+		length, err := dayLength(day)
+		if err != nil {
+			continue
+		}
+		if _, ok := lists[length]; ok {
+			continue
+		}
+		mysteries, guessables, err := loadDicts(length, alpha)
+		if err != nil {
+			return nil, nil, err
+		}
+		lists[length] = wordLists{mysteries, guessables}
+	}
+
+	return days, lists, nil
+}
+
+// printSummary prints the accuracy on the days whose answer is known
+func printSummary(stats batchStats) {
+	if stats.days == 0 {
+		return
+	}
+
+	fmt.Println()
+	fmt.Printf("Days with a known answer: %d\n", stats.days)
+	fmt.Printf("Answer among candidates:  %d (%.1f%%)\n", stats.found, percent(stats.found, stats.days))
+	fmt.Printf("Answer the only candidate: %d (%.1f%%)\n", stats.unique, percent(stats.unique, stats.days))
+	fmt.Printf("Mean candidates:          %.1f\n", stats.candidates)
+}
+
+// runBatch cracks every puzzle day in file and prints what it found, along
+// with the accuracy on the days whose answer is known
+func runBatch(file string, alpha alphabet, workers int) error {
+	days, lists, err := loadBatch(file, alpha, nil)
+	if err != nil {
+		return err
+	}
+
+	results := crackDays(days, lists, workers)
+	for _, r := range results {
+		printDay(r)
+	}
+	printSummary(summarize(results))
+
+	return nil
+}
@@ -0,0 +1,130 @@
+package main
+
+import (
+	"errors"
+	"strings"
+	"testing"
+)
+
+func TestReadBatch(t *testing.T) {
+	s := `# two days
+puzzle 1000 Cat
+gbg
+
+ggg # second player
+puzzle 2024-05-01
+dog/bbb
+`
+	days, err := readBatch(strings.NewReader(s), alphabets["en"])
+	if err != nil {
+		t.Fatalf("ERROR: expected no error, got %v", err)
+	}
+	if len(days) != 2 {
+		t.Fatalf("ERROR: expected 2 days, got %d", len(days))
+	}
+
+	testCases := []struct {
+		id     string
+		answer string
+		grids  int
+		masks  []string
+		guess  []string
+	}{
+		{"1000", "cat", 2, []string{"gbg", "ggg"}, []string{}},
+		{"2024-05-01", "", 0, []string{}, []string{"dog"}},
+	}
+
+	for i, testCase := range testCases {
+		day := days[i]
+		if day.id != testCase.id || day.answer != testCase.answer {
+			t.Errorf("ERROR: For day %d expected %s %s, got %s %s", i, testCase.id, testCase.answer, day.id, day.answer)
+		}
+		if len(day.input.grids) != testCase.grids || !equal(day.input.masks(), testCase.masks) {
+			t.Errorf("ERROR: For day %d expected %d grids %v, got %v", i, testCase.grids, testCase.masks, day.input.grids)
+		}
+		if !equal(day.input.guessWords, testCase.guess) {
+			t.Errorf("ERROR: For day %d expected guesses %v, got %v", i, testCase.guess, day.input.guessWords)
+		}
+	}
+}
+
+func TestReadBatchErrors(t *testing.T) {
+	testCases := []struct {
+		s        string
+		expected string
+	}{
+		{"gbg\n", "line 1: expected a puzzle header"},
+		{"puzzle\n", "line 1: expected puzzle <date or number>"},
+		{"puzzle 1 cat extra\n", "line 1: expected puzzle <date or number>"},
+		{"puzzle #12 cat\n", "line 1: expected puzzle <date or number>"},
+		{"puzzle 1\ngqg\n", "line 2:"},
+	}
+
+	for _, testCase := range testCases {
+		_, err := readBatch(strings.NewReader(testCase.s), alphabets["en"])
+		if err == nil || !strings.Contains(err.Error(), testCase.expected) {
+			t.Errorf("ERROR: For %q expected '%s', got %v", testCase.s, testCase.expected, err)
+		}
+	}
+}
+
+func TestCrackDayErrors(t *testing.T) {
+	words := wordLists{consistencyTestWords, consistencyTestWords}
+
+	testCases := []struct {
+		masks      []string
+		guessWords []string
+		guessMasks []string
+		expected   string
+	}{
+		{[]string{"ggb"}, []string{}, []string{}, "colorbar ggb rules out every mystery word"},
+		{[]string{"ggg"}, []string{"cat", "dog"}, []string{"ggg", "ggg"}, "these guesses conflict: cat/ggg, dog/ggg"},
+	}
+
+	for _, testCase := range testCases {
+		in := puzzleInput{grids: [][]string{testCase.masks}, guessWords: testCase.guessWords, guessMasks: testCase.guessMasks}
+		r := crackDay(puzzleDay{"1", "", in}, words)
+		if r.err == nil || !errors.Is(r.err, errNoCandidates) || !strings.Contains(r.err.Error(), testCase.expected) {
+			t.Errorf("ERROR: For %v %v %v expected an error containing %q, got %v", testCase.masks, testCase.guessWords, testCase.guessMasks, testCase.expected, r.err)
+		}
+	}
+}
+
+func TestCrackDays(t *testing.T) {
+	words := wordLists{consistencyTestWords, consistencyTestWords}
+	lists := map[int]wordLists{3: words}
+
+	in := func(masks []string, guessWords, guessMasks []string) puzzleInput {
+		return puzzleInput{grids: [][]string{masks}, guessWords: guessWords, guessMasks: guessMasks}
+	}
+	days := []puzzleDay{
+		{"1", "cot", in([]string{"gbg"}, []string{}, []string{})},
+		{"2", "", in([]string{"ggg"}, []string{"dog"}, []string{"bbb"})},
+		{"3", "cat", in([]string{"ggg"}, []string{"cat"}, []string{"bbb"})},
+		{"4", "dog", in([]string{"ggb"}, []string{}, []string{})},
+	}
+
+	expected := [][]string{
+		{"cat", "cot"},
+		{"cat", "act"},
+		{"dog"},
+		{},
+	}
+
+	for _, workers := range []int{0, 1, 3} {
+		results := crackDays(days, lists, workers)
+		for i, r := range results {
+			if r.day.id != days[i].id || !equal(r.candidates, expected[i]) {
+				t.Errorf("ERROR: For day %s with %d workers expected %v, got %s %v", days[i].id, workers, expected[i], r.day.id, r.candidates)
+			}
+		}
+		if results[3].err == nil {
+			t.Errorf("ERROR: For day 4 expected an error, got nil")
+		}
+
+		stats := summarize(results)
+		if stats != (batchStats{days: 3, found: 1, unique: 0, candidates: 1}) {
+			t.Errorf("ERROR: For %d workers got stats %v", workers, stats)
+		}
+	}
+}
@@ -0,0 +1,173 @@
+package main
+
+import (
+	"crypto/sha256"
+	"encoding/hex"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"io/fs"
+	"os"
+	"unicode/utf8"
+)
+
+// openingBook holds the precomputed first guess and the second guess for each
+// colorbar the first guess can produce. It is only valid for the word list,
+// strategy, feedback rule, probes and tie-breaking it was generated with.
+type openingBook struct {
+	Hash             string            `json:"hash"`
+	Strategy         string            `json:"strategy"`
+	Feedback         string            `json:"feedback"`
+	Probes           string            `json:"probes,omitempty"`
+	PreferCandidates bool              `json:"preferCandidates"`
+	Opener           string            `json:"opener"`
+	Second           map[string]string `json:"second"`
+
+	excluded map[string]bool // past answers the book must not suggest
+}
+
+// dictHash returns a hash that identifies the contents of a word list
+func dictHash(words []string) string {
+	h := sha256.New()
+
+	for _, word := range words {
+		h.Write([]byte(word))
+		h.Write([]byte{'\n'})
+	}
+
+	return hex.EncodeToString(h.Sum(nil))
+}
+
+// probesHash returns a hash of the probes the strategies may guess for words
+// of the same length as these, or "" if there are none
+func probesHash(words []string) string {
+	if len(words) == 0 {
+		return ""
+	}
+	pool := probes[utf8.RuneCountInString(words[0])]
+	if len(pool) == 0 {
+		return ""
+	}
+
+	return dictHash(pool)
+}
+
+// makeBook returns the opening book for the given words and strategy
+func makeBook(words []string, strategy string) (openingBook, error) {
+	suggest, ok := strategies[strategy]
+	if !ok {
+		return openingBook{}, fmt.Errorf("unknown strategy %s", strategy)
+	}
+
+	book := openingBook{
+		Hash:             dictHash(words),
+		Strategy:         strategy,
+		Feedback:         feedback.name(),
+		Probes:           probesHash(words),
+		PreferCandidates: preferCandidates,
+		Opener:           suggest(words, history{}),
+		Second:           map[string]string{},
+	}
+
+	// Every mystery word produces some colorbar for the opener. Work out the
+	// best follow-up for each distinct colorbar.
+	for _, mystery := range words {
+		mask := makeMask(mystery, book.Opener)
+		if _, ok := book.Second[mask]; ok || mystery == book.Opener {
+			continue
+		}
+		pruned := pruneGuessables(words, book.Opener, mask)
+		book.Second[mask] = suggest(pruned, history{{book.Opener, mask}})
+	}
+
+	return book, nil
+}
+
+// saveBook writes the opening book to file
+func saveBook(book openingBook, file string) error {
+	raw, err := json.MarshalIndent(book, "", "  ")
+	if err != nil {
+		return err
+	}
+
+	return os.WriteFile(file, raw, 0644)
+}
+
+// loadBook returns the opening book stored in file, or nil if there is none
+func loadBook(file string) (*openingBook, error) {
+	raw, err := os.ReadFile(file)
+	if errors.Is(err, fs.ErrNotExist) {
+		return nil, nil
+	}
+	if err != nil {
+		return nil, err
+	}
+
+	book := &openingBook{}
+	err = json.Unmarshal(raw, book)
+	if err != nil {
+		return nil, fmt.Errorf("unable to parse opening book %s: %v", file, err)
+	}
+
+	return book, nil
+}
+
+// covers returns true if the book was generated for these words and strategy
+// under the current feedback rule, probes and tie-breaking
+func (b *openingBook) covers(words []string, strategy string) bool {
+	if b == nil {
+		return false
+	}
+
+	return b.Strategy == strategy && b.Feedback == feedback.name() && b.Hash == dictHash(words) &&
+		b.Probes == probesHash(words) && b.PreferCandidates == preferCandidates
+}
+
+// bookFor returns the book if it may be used to solve from pool. The game
+// never repeats an answer, so the mystery words are those loaded from the
+// dictionary less the past answers; the book is made from the words as loaded
+// so that the same book serves every day, but it never suggests a past answer.
+func bookFor(b *openingBook, loaded, mysteries, pool []string, strategy string) *openingBook {
+	if len(pool) == len(mysteries) && b.covers(loaded, strategy) {
+		if len(loaded) == len(mysteries) {
+			return b
+		}
+		remaining := map[string]bool{}
+		for _, word := range mysteries {
+			remaining[word] = true
+		}
+		c := *b
+		c.excluded = map[string]bool{}
+		for _, word := range loaded {
+			if !remaining[word] {
+				c.excluded[word] = true
+			}
+		}
+		return &c
+	}
+	if b.covers(pool, strategy) {
+		return b
+	}
+
+	return nil
+}
+
+// guess returns the book's suggestion for the given guess history, if it has
+// one that is not a past answer
+func (b *openingBook) guess(h history) (string, bool) {
+	guess, ok := "", false
+
+	switch len(h) {
+	case 0:
+		guess, ok = b.Opener, b.Opener != ""
+	case 1:
+		if h[0].word == b.Opener {
+			guess, ok = b.Second[h[0].mask]
+		}
+	}
+	if b.excluded[guess] {
+		return "", false
+	}
+
+	return guess, ok
+}
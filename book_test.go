@@ -0,0 +1,195 @@
+package main
+
+import (
+	"path/filepath"
+	"testing"
+)
+
+func TestDictHash(t *testing.T) {
+	testCases := []struct {
+		a        []string
+		b        []string
+		expected bool
+	}{
+		{[]string{}, []string{}, true},
+		{[]string{"abc"}, []string{"abc"}, true},
+		{[]string{"abc"}, []string{"abd"}, false},
+		{[]string{"ab", "c"}, []string{"a", "bc"}, false},
+		{[]string{"abc", "def"}, []string{"def", "abc"}, false},
+	}
+
+	for _, testCase := range testCases {
+		answer := dictHash(testCase.a) == dictHash(testCase.b)
+		if answer != testCase.expected {
+			t.Errorf("ERROR: For %v %v expected %t, got %t", testCase.a, testCase.b, testCase.expected, answer)
+		}
+	}
+}
+
+func TestMakeBook(t *testing.T) {
+	words := []string{"cat", "cot", "dog", "tag"}
+
+	book, err := makeBook(words, "letterFreq")
+	if err != nil {
+		t.Fatalf("ERROR: expected no error, got %v", err)
+	}
+	if book.Opener != suggestGuessLetterFreq(words, history{}) {
+		t.Errorf("ERROR: expected opener %s, got %s", suggestGuessLetterFreq(words, history{}), book.Opener)
+	}
+	for _, mystery := range words {
+		if mystery == book.Opener {
+			continue
+		}
+		mask := makeMask(mystery, book.Opener)
+		expected := suggestGuessLetterFreq(pruneGuessables(words, book.Opener, mask), history{{book.Opener, mask}})
+		if book.Second[mask] != expected {
+			t.Errorf("ERROR: For %s expected second guess %s, got %s", mask, expected, book.Second[mask])
+		}
+	}
+
+	_, err = makeBook(words, "noSuchStrategy")
+	if err == nil {
+		t.Errorf("ERROR: For unknown strategy expected an error, got nil")
+	}
+}
+
+func TestBookGuess(t *testing.T) {
+	book := &openingBook{
+		Opener: "cat",
+		Second: map[string]string{"bbb": "dog", "gbb": "cot"},
+	}
+
+	testCases := []struct {
+		words    []string
+		masks    []string
+		expected string
+		ok       bool
+	}{
+		{[]string{}, []string{}, "cat", true},
+		{[]string{"cat"}, []string{"bbb"}, "dog", true},
+		{[]string{"cat"}, []string{"gbb"}, "cot", true},
+		{[]string{"cat"}, []string{"ggb"}, "", false},
+		{[]string{"dog"}, []string{"bbb"}, "", false},
+		{[]string{"cat", "dog"}, []string{"bbb", "bbb"}, "", false},
+	}
+
+	for _, testCase := range testCases {
+		answer, ok := book.guess(newHistory(testCase.words, testCase.masks))
+		if answer != testCase.expected || ok != testCase.ok {
+			t.Errorf("ERROR: For %v %v expected %s %t, got %s %t", testCase.words, testCase.masks, testCase.expected, testCase.ok, answer, ok)
+		}
+	}
+}
+
+func TestBookCovers(t *testing.T) {
+	words := []string{"cat", "dog"}
+	book := &openingBook{Hash: dictHash(words), Strategy: "letterFreq", Feedback: "standard", PreferCandidates: true}
+
+	if !book.covers(words, "letterFreq") {
+		t.Errorf("ERROR: expected book to cover %v", words)
+	}
+	if book.covers([]string{"cat"}, "letterFreq") {
+		t.Errorf("ERROR: expected book not to cover a different word list")
+	}
+	if book.covers(words, "other") {
+		t.Errorf("ERROR: expected book not to cover a different strategy")
+	}
+
+	defer func(r feedbackRule) { feedback = r }(feedback)
+	feedback = everyRule{}
+	if book.covers(words, "letterFreq") {
+		t.Errorf("ERROR: expected book not to cover a different feedback rule")
+	}
+
+	feedback = standardRule{}
+	defer func() {
+		probes = map[int][]string{}
+		preferCandidates = true
+	}()
+	probes = map[int][]string{3: {"cat", "dog", "zzz"}}
+	if book.covers(words, "letterFreq") {
+		t.Errorf("ERROR: expected book not to cover different probes")
+	}
+	probed, _ := makeBook(words, "letterFreq")
+	if !probed.covers(words, "letterFreq") {
+		t.Errorf("ERROR: expected book made with probes to cover them")
+	}
+	probes = map[int][]string{}
+	preferCandidates = false
+	if book.covers(words, "letterFreq") {
+		t.Errorf("ERROR: expected book not to cover a different tie-breaking")
+	}
+
+	var none *openingBook
+	if none.covers(words, "letterFreq") {
+		t.Errorf("ERROR: expected nil book not to cover anything")
+	}
+}
+
+func TestSaveLoadBook(t *testing.T) {
+	file := filepath.Join(t.TempDir(), "opening.book")
+
+	book, err := loadBook(file)
+	if book != nil || err != nil {
+		t.Errorf("ERROR: For missing file expected nil, nil, got %v, %v", book, err)
+	}
+
+	expected, _ := makeBook([]string{"cat", "cot", "dog"}, "letterFreq")
+	err = saveBook(expected, file)
+	if err != nil {
+		t.Fatalf("ERROR: expected no error saving, got %v", err)
+	}
+
+	book, err = loadBook(file)
+	if err != nil {
+		t.Fatalf("ERROR: expected no error loading, got %v", err)
+	}
+	if book.Hash != expected.Hash || book.Opener != expected.Opener || len(book.Second) != len(expected.Second) {
+		t.Errorf("ERROR: expected %v, got %v", expected, *book)
+	}
+}
+
+func TestBookFor(t *testing.T) {
+	loaded := []string{"cat", "cot", "dog"}
+	mysteries := []string{"cat", "dog"} // cot was a past answer
+	guessables := []string{"cat", "cot", "dog", "zzz"}
+	book := &openingBook{Hash: dictHash(loaded), Strategy: "letterFreq", Feedback: "standard", PreferCandidates: true, Opener: "cot"}
+
+	testCases := []struct {
+		b        *openingBook
+		pool     []string
+		strategy string
+		expected bool
+	}{
+		{book, mysteries, "letterFreq", true},
+		{book, mysteries, "other", false},
+		{book, loaded, "letterFreq", true},
+		// Relaxed to words the book was not made for
+		{book, guessables, "letterFreq", false},
+		{nil, mysteries, "letterFreq", false},
+	}
+
+	for _, testCase := range testCases {
+		answer := bookFor(testCase.b, loaded, mysteries, testCase.pool, testCase.strategy)
+		if (answer != nil) != testCase.expected {
+			t.Errorf("ERROR: For %v %s expected a book %t, got %v", testCase.pool, testCase.strategy, testCase.expected, answer)
+		}
+	}
+
+	// The book must not suggest the past answer, though it may when there
+	// are none
+	if guess, ok := bookFor(book, loaded, mysteries, mysteries, "letterFreq").guess(history{}); ok {
+		t.Errorf("ERROR: expected no opener from the book, got %s", guess)
+	}
+	if guess, ok := book.guess(history{}); !ok || guess != "cot" {
+		t.Errorf("ERROR: expected the book's opener cot, got %s %t", guess, ok)
+	}
+	if bookFor(book, loaded, loaded, loaded, "letterFreq") != book {
+		t.Errorf("ERROR: expected the book itself when there are no past answers")
+	}
+
+	other := &openingBook{Hash: dictHash(mysteries), Strategy: "letterFreq", Feedback: "standard", PreferCandidates: true}
+	if bookFor(other, loaded, mysteries, mysteries, "letterFreq") != other {
+		t.Errorf("ERROR: expected a book made without the past answers to be used")
+	}
+}
@@ -0,0 +1,124 @@
+package main
+
+import (
+	"fmt"
+	"sort"
+	"strings"
+)
+
+// typo is a change to a single tile of one mask that makes the guess history
+// consistent again
+type typo struct {
+	pair  int    // index of the turn to change
+	mask  string // what the mask should have been
+	words int    // how many words agree with the corrected history
+}
+
+// consistent returns the words that agree with every turn of the history
+func consistent(words []string, h history) []string {
+	for _, t := range h {
+		words = pruneGuessables(words, t.word, t.mask)
+		if len(words) == 0 {
+			break
+		}
+	}
+
+	return words
+}
+
+// pick returns the turns of h at the given indexes
+func pick(h history, indexes []int) history {
+	picked := history{}
+
+	for _, i := range indexes {
+		picked = append(picked, h[i])
+	}
+
+	return picked
+}
+
+// conflicts returns the indexes of a minimal set of turns that no word agrees
+// with. Removing any one of them makes the set consistent. If the whole
+// history is consistent it returns nil.
+func conflicts(words []string, h history) []int {
+	if len(consistent(words, h)) > 0 {
+		return nil
+	}
+
+	core := []int{}
+	for i := range h {
+		core = append(core, i)
+	}
+
+	// Drop each turn in turn, keeping it out if the rest still conflict
+	for i := 0; i < len(core); {
+		rest := append(append([]int{}, core[:i]...), core[i+1:]...)
+		if len(consistent(words, pick(h, rest))) == 0 {
+			core = rest
+			continue
+		}
+		i++
+	}
+
+	return core
+}
+
+// findTypos returns the single-tile changes to the masks of the given turns
+// that make the history consistent, the most likely first. A correction is
+// more likely the more words agree with it.
+func findTypos(words []string, h history, pairs []int) []typo {
+	typos := []typo{}
+
+	for _, pair := range pairs {
+		corrected := append(history{}, h...)
+		length := len([]rune(h[pair].word))
+		original := []rune(h[pair].mask)
+
+		for i := range original {
+			for _, r := range maskRunes {
+				if r == original[i] {
+					continue
+				}
+				changed := append([]rune{}, original...)
+				changed[i] = r
+				if ok, _ := validMask(string(changed), length); !ok {
+					continue
+				}
+				corrected[pair].mask = string(changed)
+				if n := len(consistent(words, corrected)); n > 0 {
+					typos = append(typos, typo{pair, string(changed), n})
+				}
+			}
+		}
+	}
+
+	sort.SliceStable(typos, func(i, j int) bool {
+		return typos[i].words > typos[j].words
+	})
+
+	return typos
+}
+
+// checkHistory returns an error describing why no word agrees with the guess
+// history, or nil if some word does
+func checkHistory(words []string, h history) error {
+	core := conflicts(words, h)
+	if core == nil {
+		return nil
+	}
+
+	pairs := []string{}
+	for _, i := range core {
+		pairs = append(pairs, h[i].String())
+	}
+	msg := fmt.Sprintf("no word agrees with the guess history; these guesses conflict: %s", strings.Join(pairs, ", "))
+
+	typos := findTypos(words, h, core)
+	if len(typos) == 0 {
+		return fmt.Errorf("%w: %s; no single-tile typo explains it", errNoCandidates, msg)
+	}
+
+	best := typos[0]
+	fixed := turn{h[best.pair].word, best.mask}
+	return fmt.Errorf("%w: %s; did you mean %s instead of %s? (leaves %d words)", errNoCandidates, msg, fixed, h[best.pair], best.words)
+}
@@ -0,0 +1,133 @@
+package main
+
+import (
+	"strings"
+	"testing"
+)
+
+func equalInts(a, b []int) bool {
+	if len(a) != len(b) {
+		return false
+	}
+
+	for i := range a {
+		if a[i] != b[i] {
+			return false
+		}
+	}
+
+	return true
+}
+
+var consistencyTestWords = []string{"cat", "cot", "dog", "tag", "act"}
+
+func TestConsistent(t *testing.T) {
+	testCases := []struct {
+		g        []string
+		m        []string
+		expected []string
+	}{
+		{[]string{}, []string{}, consistencyTestWords},
+		{[]string{"cat"}, []string{"ggg"}, []string{"cat"}},
+		{[]string{"cat"}, []string{"gbg"}, []string{"cot"}},
+		{[]string{"cat", "dog"}, []string{"gbg", "bgb"}, []string{"cot"}},
+		{[]string{"cat", "dog"}, []string{"gbg", "ggg"}, []string{}},
+	}
+
+	for _, testCase := range testCases {
+		answer := consistent(consistencyTestWords, newHistory(testCase.g, testCase.m))
+		if !equal(answer, testCase.expected) {
+			t.Errorf("ERROR: For %v %v expected %v, got %v", testCase.g, testCase.m, testCase.expected, answer)
+		}
+	}
+}
+
+func TestConflicts(t *testing.T) {
+	testCases := []struct {
+		g        []string
+		m        []string
+		expected []int
+	}{
+		{[]string{}, []string{}, nil},
+		{[]string{"cat"}, []string{"gbg"}, nil},
+		{[]string{"cat"}, []string{"ggb"}, []int{0}},
+		{[]string{"cat", "dog"}, []string{"gbg", "ggg"}, []int{0, 1}},
+		{[]string{"dog", "cat", "tag"}, []string{"bbb", "gbg", "ggg"}, []int{1, 2}},
+		{[]string{"dog", "cat", "act"}, []string{"bgb", "gbg", "ggg"}, []int{1, 2}},
+	}
+
+	for _, testCase := range testCases {
+		answer := conflicts(consistencyTestWords, newHistory(testCase.g, testCase.m))
+		if !equalInts(answer, testCase.expected) {
+			t.Errorf("ERROR: For %v %v expected %v, got %v", testCase.g, testCase.m, testCase.expected, answer)
+		}
+	}
+}
+
+func TestFindTypos(t *testing.T) {
+	testCases := []struct {
+		g        []string
+		m        []string
+		pairs    []int
+		expected []typo
+	}{
+		{[]string{"cat"}, []string{"ggb"}, []int{0}, []typo{{0, "ggg", 1}}},
+		{[]string{"dog", "cat"}, []string{"bgb", "gbb"}, []int{0, 1}, []typo{{1, "gbg", 1}}},
+		{[]string{"zzz"}, []string{"ggg"}, []int{0}, []typo{}},
+	}
+
+	for _, testCase := range testCases {
+		answer := findTypos(consistencyTestWords, newHistory(testCase.g, testCase.m), testCase.pairs)
+		if len(answer) != len(testCase.expected) {
+			t.Errorf("ERROR: For %v %v expected %v, got %v", testCase.g, testCase.m, testCase.expected, answer)
+			continue
+		}
+		for i := range answer {
+			if answer[i] != testCase.expected[i] {
+				t.Errorf("ERROR: For %v %v expected %v, got %v", testCase.g, testCase.m, testCase.expected, answer)
+			}
+		}
+	}
+}
+
+func TestFindTyposRanksByWords(t *testing.T) {
+	words := []string{"abc", "abd", "abe", "xbz"}
+
+	// The first mask is impossible. Changing its last tile leaves three words,
+	// changing its first tile leaves only one.
+	answer := findTypos(words, history{{"abq", "ggg"}}, []int{0})
+	if len(answer) == 0 || answer[0] != (typo{0, "ggb", 3}) {
+		t.Errorf("ERROR: expected {0 ggb 3} first, got %v", answer)
+	}
+}
+
+func TestCheckHistory(t *testing.T) {
+	testCases := []struct {
+		g        []string
+		m        []string
+		expected []string
+	}{
+		{[]string{"cat"}, []string{"gbg"}, nil},
+		{[]string{"dog", "cat"}, []string{"bgb", "gbb"}, []string{"conflict: cat/gbb;", "cat/gbg instead of cat/gbb", "leaves 1 words"}},
+		{[]string{"zzz"}, []string{"ggg"}, []string{"conflict: zzz/ggg", "no single-tile typo"}},
+	}
+
+	for _, testCase := range testCases {
+		err := checkHistory(consistencyTestWords, newHistory(testCase.g, testCase.m))
+		if testCase.expected == nil {
+			if err != nil {
+				t.Errorf("ERROR: For %v %v expected no error, got %v", testCase.g, testCase.m, err)
+			}
+			continue
+		}
+		if err == nil {
+			t.Errorf("ERROR: For %v %v expected an error, got nil", testCase.g, testCase.m)
+			continue
+		}
+		for _, want := range testCase.expected {
+			if !strings.Contains(err.Error(), want) {
+				t.Errorf("ERROR: For %v %v expected '%s' in %v", testCase.g, testCase.m, want, err)
+			}
+		}
+	}
+}
@@ -1,94 +1,211 @@
 package main
 
-// go fmt ./... && go vet ./... && go test ./... && go run cracker.go -colorbars=bbbyy,yybbb -cpuprofile cpu.prof && echo top | go tool pprof cpu.prof
+// go fmt ./... && go vet ./... && go test ./... && go run . -colorbars=bbbyy,yybbb -cpuprofile cpu.prof && echo top | go tool pprof cpu.prof
 
 import (
+	"errors"
 	"flag"
 	"fmt"
 	"log"
 	"os"
+	"runtime"
 	"runtime/pprof"
 	"strings"
+	"time"
+	"unicode/utf8"
 
 	"github.com/erikbryant/dictionaries"
 )
 
 var (
-	cpuprofile  = flag.String("cpuprofile", "", "write cpu profile to file")
-	colorbars   = flag.String("colorbars", "ggggg", "colorbars from previous games in the form of yyybb,ygbyy,... (omit the final ggggg)")
-	guessed     = flag.String("guessed", "", "comma-separated list of guess/colorbar pairs e.g., foo/gbb,oof/bby,...")
-	mysteryWord = flag.String("mystery", "", "the mystery word (if you know it), useful for error checking masks")
+	cpuprofile   = flag.String("cpuprofile", "", "write cpu profile to file")
+	colorbars    = flag.String("colorbars", "", "colorbars from previous games in the form of yyybb,ygbyy,... (omit the final ggggg)")
+	guessed      = flag.String("guessed", "", "comma-separated list of guess/colorbar pairs e.g., foo/gbb,oof/bby,...")
+	mysteryWord  = flag.String("mystery", "", "the mystery word (if you know it), useful for error checking masks")
+	mode         = flag.String("mode", "solve", "what to do: solve (default), play (play every word), book (generate the opening book), batch (crack every puzzle day in -input), verify (check batch against -schedule), report (measure how much colorbars help), generate (write synthetic puzzle days), tournament (compare -strategies), or answer (record -mystery in -answers)")
+	strategy     = flag.String("strategy", "letterFreq", "strategy used to suggest guesses: letterFreq, positional, minimax (smallest worst case), or expected (fewest candidates left on average)")
+	bookFile     = flag.String("book", "opening.book", "opening book file, used automatically when present")
+	length       = flag.Int("length", 0, "word length (default: inferred from colorbars/guesses, else 5; play mode runs 4 through 8)")
+	lang         = flag.String("lang", "en", "language of the words: en, es, es-accents, de, or de-folded (all but en need -dict)")
+	letters      = flag.String("alphabet", "", "letters of the alphabet (default: the language's alphabet)")
+	dict         = flag.String("dict", "", "dictionary file (default: the language's dictionary)")
+	guessFile    = flag.String("guessables", "", "file of more words that may be guessed but are never the answer, e.g. ../dictionaries/wordleGuessable.dict with -dict=../dictionaries/wordleMystery.dict")
+	relax        = flag.Bool("relax", false, "if no mystery word fits, try again treating every guessable word as a possible mystery (needs past answers or -guessables to add any words)")
+	saveFile     = flag.String("save", "", "after solving, save the session to this file")
+	resumeFile   = flag.String("resume", "", "resume the session saved in this file, adding any -colorbars and -guessed to it")
+	inputFile    = flag.String("input", "", "read colorbars and guesses from this file (- for stdin), one row per line")
+	answersFile  = flag.String("answers", "past.answers", "past answers, one \"<date or number> <word>\" per line, excluded from the mystery words when present")
+	scheduleFile = flag.String("schedule", "", "file of scheduled answers, in the same form as -answers, for -mode=verify or report")
+	playerName   = flag.String("player", "noisy", "player model for -mode=generate and -mode=report: random, noisy, casual, expert, or a strategy, then options such as opener=crane,noise=0.1,offList=0.2,vocab=3000,hard")
+	players      = flag.Int("players", 10, "number of players sharing grids each day in -mode=generate")
+	days         = flag.Int("days", 1, "number of puzzle days in -mode=generate")
+	preferCands  = flag.Bool("preferCandidates", true, "when the minimax and expected strategies find equally good guesses, prefer one that could be the answer")
+	probe        = flag.Bool("probes", false, "let the minimax and expected strategies guess any dictionary word, not only those that could be the answer (slower)")
+	seed         = flag.Int64("seed", 0, "random seed (default: from the clock)")
+	sampleSize   = flag.Int("sample", 0, "play only this many randomly chosen mystery words of each length in -mode=play or tournament (default: all of them)")
+	stratify     = flag.String("stratify", "", "sample the same share of words from each group: first (letter) or pattern (of vowels and consonants)")
+	output       = flag.String("output", "", "write generated puzzles to this file (default: stdout)")
+	contenders   = flag.String("strategies", "", "comma-separated strategies for -mode=tournament, the first being the baseline")
+	statsFile    = flag.String("stats", "", "also write the -mode=play statistics to this file")
+	format       = flag.String("format", "text", "format of the -stats file: text, csv, or json")
+	csvFile      = flag.String("csv", "", "also write the -mode=report or tournament results to this CSV file")
+	day          = flag.String("day", "", "date or puzzle number of -mystery when recording it with -mode=answer (default: today's date)")
+	workers      = flag.Int("workers", runtime.NumCPU(), "number of puzzle days to crack at once in batch mode")
+	rule         = flag.String("feedback", "standard", "how the game gives feedback: standard, every, or leftToRight colorbars, or jotto (e.g., 3) or mastermind (e.g., 2g1y) counts")
 )
 
+// Word length to use when nothing else determines it
+const defaultWordLen = 5
+
+// errNoCandidates is returned when no word agrees with all of the constraints
+var errNoCandidates = errors.New("no candidate words remain")
+
 // loadDicts returns the mystery and guessable word lists
-func loadDicts(wordLen int) ([]string, []string) {
+func loadDicts(wordLen int, alpha alphabet) ([]string, []string, error) {
 	// Even though they are identical, make a copy. Otherwise one will be a
 	// reference to the other and we will get data corruption if we ever try
 	// to manipulate the dictionaries separately.
-	mysteries := dictionaries.LoadFile("../dictionaries/merged.dict")
-	mysteries = dictionaries.FilterByLen(mysteries, wordLen)
+	mysteries, err := alpha.words()
+	if err != nil {
+		return nil, nil, err
+	}
+	mysteries = alpha.filter(mysteries, wordLen)
 	mysteries = dictionaries.SortUnique(mysteries)
+	if len(mysteries) == 0 {
+		return nil, nil, fmt.Errorf("no dictionary words of length %d", wordLen)
+	}
 
 	guessables := make([]string, len(mysteries))
 	copy(guessables, mysteries)
+	if alpha.guesses != "" {
+		more, err := alpha.load(alpha.guesses)
+		if err != nil {
+			return nil, nil, err
+		}
+		guessables = dictionaries.SortUnique(append(guessables, alpha.filter(more, wordLen)...))
+	}
 
-	return mysteries, guessables
+	return mysteries, guessables, nil
 }
 
-// validMask returns true if the mask appears to be valid
-func validMask(mask string, length int) (bool, error) {
-	if len(mask) != length {
-		return false, fmt.Errorf("masks must all be of the same length %s", mask)
+// wordLength returns the word length shared by all of the masks and guesses.
+// If length is zero it is inferred from them, falling back to defaultWordLen.
+func wordLength(length int, masks, guessWords, guessMasks []string) (int, error) {
+	if length < 0 {
+		return 0, fmt.Errorf("word length %d must not be negative", length)
+	}
+	allMasks := append(append([]string{}, masks...), guessMasks...)
+
+	if length == 0 && len(guessWords) > 0 {
+		length = utf8.RuneCountInString(guessWords[0])
+	}
+	if length == 0 {
+		// Only some kinds of mask imply a word length
+		for _, mask := range allMasks {
+			if length = feedback.length(mask); length != 0 {
+				break
+			}
+		}
+	}
+	if length == 0 {
+		length = defaultWordLen
 	}
 
-	for _, val := range mask {
-		switch val {
-		case 'g':
-		case 'y':
-		case 'b':
-		default:
-			return false, fmt.Errorf("masks must contain only g, y, or b %s %c", mask, val)
+	for _, word := range guessWords {
+		if utf8.RuneCountInString(word) != length {
+			return 0, fmt.Errorf("expected length %d, got %s", length, word)
+		}
+	}
+	for _, mask := range allMasks {
+		if ok, err := validMask(mask, length); !ok {
+			return 0, fmt.Errorf("expected length %d: %v", length, err)
 		}
 	}
 
-	return true, nil
+	return length, nil
+}
+
+// validMask returns true if the mask appears to be valid under the feedback rule
+func validMask(mask string, length int) (bool, error) {
+	err := feedback.valid(mask, length)
+	return err == nil, err
 }
 
-// unpackMasks returns the command line masks representation in slices
+// unpackMasks returns the command line masks representation in slices. Masks
+// may be separated by commas, semicolons or whitespace, and may use any of the
+// tile aliases. Every problem found is reported in the error.
 func unpackMasks(s string) ([]string, error) {
-	masks := strings.Split(s, ",")
+	masks := []string{}
+	p := problems{}
 
-	for _, m := range masks {
-		if ok, err := validMask(m, len(masks[0])); !ok {
-			return nil, err
+	// All masks must be the length of the first one
+	length := -1
+
+	for _, t := range tokenize(s) {
+		mask, ok := canonicalMask(t, &p)
+		if !ok {
+			continue
+		}
+		if length < 0 {
+			length = feedback.length(mask)
 		}
+		if ok, err := validMask(mask, length); !ok {
+			p.add(t.col(), "%v", err)
+			continue
+		}
+		masks = append(masks, mask)
+	}
+
+	if err := p.err(); err != nil {
+		return nil, err
 	}
 
 	return dictionaries.SortUnique(masks), nil
 }
 
-// unpackGuessed returns the words guessed and the resulting colorbar masks in slices
-func unpackGuessed(guessedPairs string) ([]string, []string, error) {
+// unpackGuessed returns the normalized words guessed and the resulting colorbar
+// masks in slices. Pairs may be written guess/mask, guess:mask or guess=mask,
+// and are separated like masks. Every problem found is reported in the error.
+func unpackGuessed(guessedPairs string, alpha alphabet) ([]string, []string, error) {
 	guessWords := []string{}
 	guessMasks := []string{}
+	p := problems{}
 
-	for _, pair := range strings.Split(guessedPairs, ",") {
-		s := strings.Split(pair, "/")
-		if len(s) != 2 {
-			return nil, nil, fmt.Errorf("too many/few slash-delimited values %s %v", pair, s)
+	for _, t := range tokenize(guessedPairs) {
+		w, m, ok := splitPair(t)
+		if !ok {
+			p.add(t.col(), "expected a single guess/mask pair %s", t.text)
+			continue
 		}
-		if ok, err := validMask(s[1], len(s[0])); !ok {
-			return nil, nil, err
+		word := alpha.normalize(w.text)
+		if word == "" {
+			p.add(w.col(), "guess has no letters %s", t.text)
+			continue
 		}
-		guessWords = append(guessWords, s[0])
-		guessMasks = append(guessMasks, s[1])
+		mask, ok := canonicalMask(m, &p)
+		if !ok {
+			continue
+		}
+		if ok, err := validMask(mask, utf8.RuneCountInString(word)); !ok {
+			p.add(m.col(), "%v", err)
+			continue
+		}
+		guessWords = append(guessWords, word)
+		guessMasks = append(guessMasks, mask)
+	}
+
+	if len(guessWords) == 0 && len(p) == 0 {
+		p.add(1, "no guesses found %s", guessedPairs)
+	}
+	if err := p.err(); err != nil {
+		return nil, nil, err
 	}
 
 	return guessWords, guessMasks, nil
 }
 
 // replace replaces the first instance of a with b in w
-func replace(w []byte, a, b byte) {
+func replace(w []rune, a, b rune) {
 	for i := range w {
 		if w[i] == a {
 			w[i] = b
@@ -98,7 +215,7 @@ func replace(w []byte, a, b byte) {
 }
 
 // contains returns true if b is in w
-func contains(w []byte, b byte) bool {
+func contains(w []rune, b rune) bool {
 	for _, val := range w {
 		if val == b {
 			return true
@@ -109,77 +226,28 @@ func contains(w []byte, b byte) bool {
 
 // matchSingleWord returns true if candidate is not ruled out based on word/mask
 func matchSingleWord(word, mask, candidate string) bool {
-	if len(word) != len(mask) || len(word) != len(candidate) {
+	if utf8.RuneCountInString(word) != utf8.RuneCountInString(candidate) {
 		fmt.Println("Internal consistency error!", word, mask, candidate)
 		return false
 	}
 
-	// We will mask out some of the letters in word. Store that in w.
-	w := make([]byte, len(word))
-
-	// Evaluate 'g' masks
-	for i, m := range mask {
-		switch m {
-		case 'g':
-			if word[i] != candidate[i] {
-				return false
-			}
-			// This letter has been "spoken for", mark it as such
-			w[i] = '_'
-			continue
-		}
-
-		w[i] = word[i]
-	}
-
-	// Evaluate 'y' masks
-	for i, m := range mask {
-		if m != 'y' {
-			continue
-		}
-
-		// The candidate letter must be in word...
-		if !contains(w, candidate[i]) {
-			return false
-		}
-
-		// ...but it can't be *this* letter
-		if w[i] == candidate[i] {
-			return false
-		}
-
-		// Mark the letter as having been "spoken for"
-		replace(w, candidate[i], '_')
-	}
-
-	// Evaluate 'b' masks
-	for i, m := range mask {
-		switch m {
-		case 'b':
-			// Only look at letters that are not already spoken for
-			if strings.ContainsRune(string(w), rune(candidate[i])) {
-				return false
-			}
-		}
-	}
-
-	return true
+	return makeMask(word, candidate) == mask
 }
 
 // matchMasks returns whether any candidate words match the word/masks pair
 func matchMasks(word string, masks, candidates []string) bool {
-	matches := make([][]string, len(masks))
-
-	// Rule out the cases where candidates do not match the masks
-	for i, mask := range masks {
+	// Rule out the cases where no candidate matches one of the masks
+	for _, mask := range masks {
+		found := false
 		for _, candidate := range candidates {
-			if !matchSingleWord(word, mask, candidate) {
-				continue
+			if matchSingleWord(word, mask, candidate) {
+				// One match is enough to show the mask is possible
+				found = true
+				break
 			}
-			matches[i] = append(matches[i], candidate)
 		}
 
-		if len(matches[i]) == 0 {
+		if !found {
 			return false
 		}
 	}
@@ -202,7 +270,7 @@ func applyMasks(mysteries, guessables, masks []string) []string {
 }
 
 // scoreWord returns the sum of unique letter frequencies for a given word
-func scoreWord(word string, freq []int) int {
+func scoreWord(word string, freq map[rune]int) int {
 	used := map[rune]bool{}
 	score := 0
 
@@ -210,7 +278,7 @@ func scoreWord(word string, freq []int) int {
 		if used[val] {
 			continue
 		}
-		score += freq[byte(val)]
+		score += freq[val]
 		used[val] = true
 	}
 
@@ -223,7 +291,7 @@ type score struct {
 }
 
 // scoreWords returns the scores for each word and the words with the max score
-func scoreWords(words []string, lFreq []int) ([]string, int, []score) {
+func scoreWords(words []string, lFreq map[rune]int) ([]string, int, []score) {
 	maxScore := 0
 	maxWords := []string{}
 	scores := make([]score, len(words))
@@ -258,6 +326,13 @@ func printStats(matches, masks []string, message string) {
 		fmt.Println()
 	}
 
+	if len(matches) == 0 {
+		fmt.Printf("Found no matches for masks %v\n", masks)
+		fmt.Println("===================================================")
+		fmt.Println()
+		return
+	}
+
 	samples := 10
 	if samples > len(matches) {
 		samples = len(matches)
@@ -265,15 +340,15 @@ func printStats(matches, masks []string, message string) {
 	fmt.Printf("Found %d matches for masks %v, printing first few...\n", len(matches), masks)
 	fmt.Println(matches[:samples])
 
-	lFreq, lByPos := dictionaries.LetterFrequency(matches)
+	lFreq, lByPos := letterFrequency(matches)
 
 	fmt.Println("Letter frequency by position:")
 	for i, pos := range lByPos {
-		fmt.Printf("  [%d] %s\n", i, dictionaries.PrettyPrintFreq(pos))
+		fmt.Printf("  [%d] %s\n", i, prettyPrintFreq(pos))
 	}
 
 	fmt.Println("Letter frequency overall:")
-	fmt.Print(dictionaries.PrettyPrintFreq(lFreq))
+	fmt.Print(prettyPrintFreq(lFreq))
 
 	maxWords, maxScore, _ := scoreWords(matches, lFreq)
 	fmt.Printf("\nSuggested guess(es): %v for a score of %d\n", maxWords, maxScore)
@@ -282,10 +357,35 @@ func printStats(matches, masks []string, message string) {
 	fmt.Println()
 }
 
+// emptiedBy returns the first of the masks that, applied in order, leaves no
+// mystery words, or "" if there are matches after all of them
+func emptiedBy(mysteries, guessables, masks []string) string {
+	for _, mask := range masks {
+		mysteries = applyMasks(mysteries, guessables, []string{mask})
+		if len(mysteries) == 0 {
+			return mask
+		}
+	}
+
+	return ""
+}
+
+// noMatches returns the error for when the masks leave no mystery words
+func noMatches(mysteries, guessables, masks []string) error {
+	if len(mysteries) == 0 {
+		return fmt.Errorf("%w: there are no mystery words to start from", errNoCandidates)
+	}
+
+	return fmt.Errorf("%w: colorbar %s rules out every mystery word", errNoCandidates, emptiedBy(mysteries, guessables, masks))
+}
+
 // crack eliminates all wods that do not match the masks
 func crack(mysteries, guessables, masks []string, mystery string) error {
 	// Find which mystery words can be formed using words from the guessable words
 	matches := applyMasks(mysteries, guessables, masks)
+	if len(matches) == 0 {
+		return noMatches(mysteries, guessables, masks)
+	}
 	if mystery != "" && !dictionaries.ContainsWord(matches, mystery) {
 		return fmt.Errorf("mystery word is not in matches %v %s", matches, mystery)
 	}
@@ -297,51 +397,15 @@ func crack(mysteries, guessables, masks []string, mystery string) error {
 
 // makeMask returns the byg mask for the given guess and given mystery word
 func makeMask(word, guess string) string {
-	m := make([]byte, len(word))
-	w := make([]byte, len(word))
-
-	for i, val := range word {
-		w[i] = byte(val)
-	}
-
-	// g
-	for i := range word {
-		if word[i] != guess[i] {
-			continue
-		}
-		m[i] = 'g'
-		w[i] = '_'
-	}
-
-	// y and b
-	for i := range word {
-		if m[i] != 0 {
-			continue
-		}
-
-		if contains(w, guess[i]) {
-			m[i] = 'y'
-			replace(w, guess[i], '_')
-			continue
-		}
-
-		m[i] = 'b'
-	}
-
-	mask := ""
-	for _, val := range m {
-		mask += string(val)
-	}
-
-	return mask
+	return feedback.mask(word, guess)
 }
 
 // findMaxScore returns the highest scoring word that has not already been guessed
-func findMaxScore(scores []score, guesses string) score {
+func findMaxScore(scores []score, h history) score {
 	max := score{-1, ""}
 
 	for _, s := range scores {
-		if s.score > max.score && !strings.Contains(guesses, s.word) {
+		if s.score > max.score && !h.guessed(s.word) {
 			max.score = s.score
 			max.word = s.word
 		}
@@ -350,14 +414,22 @@ func findMaxScore(scores []score, guesses string) score {
 	return max
 }
 
-func suggestGuessLetterFreq(matches []string, guesses string) string {
-	lFreq, _ := dictionaries.LetterFrequency(matches)
+func suggestGuessLetterFreq(matches []string, h history) string {
+	lFreq, _ := letterFrequency(matches)
 	_, _, scores := scoreWords(matches, lFreq)
-	max := findMaxScore(scores, guesses)
+	max := findMaxScore(scores, h)
 
 	return max.word
 }
 
+// strategies maps strategy names to the functions that suggest the next guess
+var strategies = map[string]func(matches []string, h history) string{
+	"letterFreq": suggestGuessLetterFreq,
+	"positional": suggestGuessPositional,
+	"minimax":    suggestGuessMinimax,
+	"expected":   suggestGuessExpected,
+}
+
 func pruneGuessables(guessables []string, word, mask string) []string {
 	pruned := []string{}
 
@@ -371,60 +443,147 @@ func pruneGuessables(guessables []string, word, mask string) []string {
 	return pruned
 }
 
-func playAllWords(wordLen int) {
-	mysteries, guessables := loadDicts(wordLen)
+func playAllWords(wordLen int, alpha alphabet, strategy string, book *openingBook, s sampling) (benchStats, error) {
+	population, _, err := loadDicts(wordLen, alpha)
+	if err != nil {
+		return benchStats{}, err
+	}
+	suggest := strategies[strategy]
+	if !book.covers(population, strategy) {
+		book = nil
+	}
+
+	mysteries := s.pick(population)
+	if s.sampled(population) {
+		fmt.Printf("Playing %d of %d mystery words, seed %d\n\n", len(mysteries), len(population), s.seed)
+	}
 
 	totalGuesses := 0
 	totalWords := 0
+	games := []game{}
+	guesses := []int{}
 
 	for _, mystery := range mysteries {
-		guesses := ""
-		guessableWords := guessables
 		totalWords++
 
-		for i := 1; ; i++ {
-			guess := suggestGuessLetterFreq(guessableWords, guesses)
-			guesses += "." + guess
-			totalGuesses++
+		g := playGame(mystery, population, suggest, book)
+		games = append(games, g)
+		guesses = append(guesses, len(g.turns))
+		totalGuesses += len(g.turns)
+		if !g.found {
+			fmt.Printf("Mystery: %s  not found, no candidates remain\n", mystery)
+			continue
+		}
+		fmt.Printf("Mystery: %s  Guesses: %2d  Total Words: %5d  Average guesses: %4.2f\n", mystery, len(g.turns), totalWords, float64(totalGuesses)/float64(totalWords))
+	}
 
-			mask := makeMask(mystery, guess)
+	fmt.Printf("\nTotal Words: %5d  Average guesses: %4.2f\n", totalWords, float64(totalGuesses)/float64(totalWords))
 
-			if guess == mystery {
-				fmt.Printf("Mystery: %s  Guesses: %2d  Total Words: %5d  Average guesses: %4.2f\n", mystery, i, totalWords, float64(totalGuesses)/float64(totalWords))
-				break
-			}
+	stats := newBenchStats(wordLen, games)
+	stats.Population = len(population)
+	stats.Estimate, stats.Margin = s.estimate(population, mysteries, guesses)
+	stats.Unsampled = s.unsampled(population, mysteries)
+	printBenchStats(stats)
 
-			guessableWords = pruneGuessables(guessableWords, guess, mask)
+	return stats, nil
+}
+
+// game is one game played by a strategy
+type game struct {
+	mystery   string
+	turns     history
+	remaining []int // how many candidates were left after each turn
+	found     bool
+}
+
+// playGame plays the game for mystery, suggesting each guess from the words
+// that remain. The opening book is used unless it is nil.
+func playGame(mystery string, words []string, suggest func(matches []string, h history) string, book *openingBook) game {
+	g := game{mystery: mystery, turns: history{}, remaining: []int{}}
+
+	for {
+		guess, ok := "", false
+		if book != nil {
+			guess, ok = book.guess(g.turns)
 		}
+		if !ok {
+			guess = suggest(words, g.turns)
+		}
+		if guess == "" {
+			return g
+		}
+
+		mask := makeMask(mystery, guess)
+		g.turns = append(g.turns, turn{guess, mask})
+
+		if guess == mystery {
+			g.remaining = append(g.remaining, 1)
+			g.found = true
+			return g
+		}
+
+		words = pruneGuessables(words, guess, mask)
+		g.remaining = append(g.remaining, len(words))
 	}
+}
 
-	fmt.Printf("\nTotal Words: %5d  Average guesses: %4.2f\n", totalWords, float64(totalGuesses)/float64(totalWords))
+func benchmarkLengths(minLen, maxLen int, alpha alphabet, strategy string, book *openingBook, s sampling) ([]benchStats, error) {
+	all := []benchStats{}
+
+	for wordLen := minLen; wordLen <= maxLen; wordLen++ {
+		fmt.Printf("\n======== Word length %d ========\n\n", wordLen)
+		stats, err := playAllWords(wordLen, alpha, strategy, book, s)
+		if err != nil {
+			return nil, err
+		}
+		all = append(all, stats)
+	}
+
+	return all, nil
 }
 
-func solveOne(mysteries, guessables, masks, guessWords, guessMasks []string, mystery string) error {
+// solveOne suggests the next guess. The opening book, which must be one made
+// for the mysteries, is used unless it is nil or the colorbars have already
+// ruled out some of the mysteries.
+func solveOne(mysteries, guessables, masks []string, h history, mystery, strategy string, book *openingBook) error {
 	// Find which mystery words can be formed using words from the guessable words
 	matches := applyMasks(mysteries, guessables, masks)
+	if len(matches) == 0 {
+		return noMatches(mysteries, guessables, masks)
+	}
 	if mystery != "" && !dictionaries.ContainsWord(matches, mystery) {
 		return fmt.Errorf("mystery word has been excluded from matches %v %s", matches, mystery)
 	}
+	// Catch mistyped masks before they prune everything away
+	if err := checkHistory(matches, h); err != nil {
+		return err
+	}
 	printStats(matches, masks, "Analysis of initial masks")
+	useBook := book != nil && len(matches) == len(mysteries)
 
-	guesses := ""
-	for i := range guessWords {
-		masks = append(masks, guessMasks[i])
-		guesses += "." + guessWords[i]
+	for _, t := range h {
+		masks = append(masks, t.mask)
 
-		matches = pruneGuessables(matches, guessWords[i], guessMasks[i])
+		matches = pruneGuessables(matches, t.word, t.mask)
 		if mystery != "" && !dictionaries.ContainsWord(matches, mystery) {
-			return fmt.Errorf("mystery word: '%s' has been excluded from matches after guessing: '%s'. %v", mystery, guessWords[i], matches)
+			return fmt.Errorf("mystery word: '%s' has been excluded from matches after guessing: '%s'. %v", mystery, t.word, matches)
 		}
-		msg := fmt.Sprintf("After applying %s/%s", guessWords[i], guessMasks[i])
+		msg := fmt.Sprintf("After applying %s", t)
 		printStats(matches, masks, msg)
 		fmt.Println(matches)
 	}
 
 	fmt.Println("===================================================")
-	guess := suggestGuessLetterFreq(matches, guesses)
+	guess, ok := "", false
+	if useBook {
+		guess, ok = book.guess(h)
+	}
+	if !ok {
+		guess = strategies[strategy](matches, h)
+	}
+	if guess == "" {
+		return fmt.Errorf("%w: every remaining candidate has already been guessed %v", errNoCandidates, matches)
+	}
 	fmt.Println("Suggested guess:", guess)
 	fmt.Println("===================================================")
 	fmt.Println()
@@ -432,6 +591,23 @@ func solveOne(mysteries, guessables, masks, guessWords, guessMasks []string, mys
 	return nil
 }
 
+// withRelax runs solve over the mystery words. If that leaves no candidates
+// and relax is set, it runs it again over all of the guessable words.
+func withRelax(relax bool, mysteries, guessables []string, solve func(pool []string) error) error {
+	err := solve(mysteries)
+	if !relax || !errors.Is(err, errNoCandidates) {
+		return err
+	}
+	if len(guessables) == len(mysteries) {
+		return fmt.Errorf("%w; relaxing would add no words, give more with -guessables", err)
+	}
+
+	fmt.Println(err)
+	fmt.Println("Relaxing the mystery words to every guessable word")
+
+	return solve(guessables)
+}
+
 func main() {
 	fmt.Printf("Welcome to Cracker\n\n")
 
@@ -445,40 +621,297 @@ func main() {
 		defer pprof.StopCPUProfile()
 	}
 
-	// playAllWords(5)
-	// return
+	resumed := session{}
+	if *resumeFile != "" {
+		var err error
+		resumed, err = loadSession(*resumeFile)
+		if err == nil {
+			err = resumed.apply(flag.CommandLine)
+		}
+		if err != nil {
+			fmt.Println(err)
+			return
+		}
+	}
+
+	if _, ok := strategies[*strategy]; !ok {
+		fmt.Println("unknown strategy", *strategy)
+		return
+	}
+
+	r, ok := feedbackRules[*rule]
+	if !ok {
+		fmt.Println("unknown feedback rule", *rule)
+		return
+	}
+	feedback = r
+
+	alpha, ok := alphabets[*lang]
+	if !ok {
+		fmt.Println("unknown language", *lang)
+		return
+	}
+	if *letters != "" {
+		alpha.letters = *letters
+	}
+	if *dict != "" {
+		alpha.dict = *dict
+	}
+	if *guessFile != "" {
+		alpha.guesses = *guessFile
+	}
 
-	masks, err := unpackMasks(*colorbars)
+	book, err := loadBook(*bookFile)
 	if err != nil {
 		fmt.Println(err)
 		return
 	}
 
-	// Use only the words of appropriate length
-	wordLen := len(masks[0])
-	mysteries, guessables := loadDicts(wordLen)
+	preferCandidates = *preferCands
+	if *probe {
+		probes, err = loadProbes(alpha)
+		if err != nil {
+			fmt.Println(err)
+			return
+		}
+	}
 
-	// If there are no guesses, just find the set of matches
-	if *guessed == "" {
-		err = crack(mysteries, guessables, masks, *mysteryWord)
+	if *mode == "batch" || *mode == "verify" || *mode == "report" {
+		switch *mode {
+		case "batch":
+			err = runBatch(*inputFile, alpha, *workers)
+		case "verify":
+			err = runVerify(*inputFile, *scheduleFile, alpha, *workers)
+		case "report":
+			err = runReport(*inputFile, *scheduleFile, *csvFile, alpha, *workers, *strategy, *playerName, book)
+		}
 		if err != nil {
 			fmt.Println(err)
 		}
 		return
 	}
 
-	// There is a guess. Unpack those and start solving.
-	guessWords, guessMasks, err := unpackGuessed(*guessed)
+	masks := []string{}
+	if *colorbars != "" {
+		masks, err = unpackMasks(*colorbars)
+		if err != nil {
+			fmt.Println(err)
+			return
+		}
+	}
+
+	guessWords, guessMasks := []string{}, []string{}
+	if *guessed != "" {
+		guessWords, guessMasks, err = unpackGuessed(*guessed, alpha)
+		if err != nil {
+			fmt.Println(err)
+			return
+		}
+	}
+
+	if *inputFile != "" {
+		in, err := readInputFile(*inputFile, alpha)
+		if err != nil {
+			fmt.Println(err)
+			return
+		}
+		masks = dictionaries.SortUnique(append(masks, in.masks()...))
+		guessWords = append(guessWords, in.guessWords...)
+		guessMasks = append(guessMasks, in.guessMasks...)
+		fmt.Printf("Read %d colorbars from %d players and %d guesses\n\n", len(in.masks()), len(in.grids), len(in.guessWords))
+	}
+	given := len(masks) > 0
+
+	mystery := alpha.normalize(*mysteryWord)
+
+	for _, word := range guessWords {
+		if !alpha.valid(word) {
+			fmt.Printf("guess %s contains letters not in the alphabet %s\n", word, alpha.letters)
+			return
+		}
+	}
+
+	// Use only the words of appropriate length
+	lengthGiven := false
+	flag.Visit(func(f *flag.Flag) {
+		lengthGiven = lengthGiven || f.Name == "length"
+	})
+	if lengthGiven && *length == 0 {
+		fmt.Println("word length must be at least 1")
+		return
+	}
+	wordLen, err := wordLength(*length, masks, guessWords, guessMasks)
 	if err != nil {
 		fmt.Println(err)
 		return
 	}
-	err = solveOne(mysteries, guessables, masks, guessWords, guessMasks, *mysteryWord)
+	if len(masks) == 0 {
+		// With no colorbars every word is a possible mystery
+		masks = []string{solvedMask(wordLen)}
+	}
+
+	s := sampling{size: *sampleSize, by: *stratify, seed: *seed}
+	if s.seed == 0 {
+		s.seed = time.Now().UnixNano()
+	}
+	err = s.valid()
+	if err != nil {
+		fmt.Println(err)
+		return
+	}
+
+	switch *mode {
+	case "solve":
+	case "play":
+		all := []benchStats{}
+		if *length == 0 && !given && len(guessWords) == 0 {
+			all, err = benchmarkLengths(4, 8, alpha, *strategy, book, s)
+		} else {
+			var stats benchStats
+			stats, err = playAllWords(wordLen, alpha, *strategy, book, s)
+			all = append(all, stats)
+		}
+		if err == nil && *statsFile != "" {
+			err = saveBenchStats(all, *statsFile, *format)
+		}
+		if err != nil {
+			fmt.Println(err)
+		}
+		return
+	case "book":
+		mysteries, _, err := loadDicts(wordLen, alpha)
+		if err != nil {
+			fmt.Println(err)
+			return
+		}
+		b, err := makeBook(mysteries, *strategy)
+		if err == nil {
+			err = saveBook(b, *bookFile)
+		}
+		if err != nil {
+			fmt.Println(err)
+			return
+		}
+		fmt.Printf("Wrote opening book to %s, opener: %s\n", *bookFile, b.Opener)
+		return
+	case "tournament":
+		err = runTournament(wordLen, alpha, strings.Split(*contenders, ","), book, *workers, s, *csvFile)
+		if err != nil {
+			fmt.Println(err)
+		}
+		return
+	case "generate":
+		err = runGenerate(*output, wordLen, alpha, mystery, *playerName, *days, *players, *seed)
+		if err != nil {
+			fmt.Println(err)
+		}
+		return
+	case "answer":
+		d := *day
+		if d == "" {
+			d = time.Now().Format("2006-01-02")
+		}
+		err = appendAnswer(*answersFile, pastAnswer{d, mystery}, alpha)
+		if err != nil {
+			fmt.Println(err)
+			return
+		}
+		fmt.Printf("Recorded %s as the answer for %s in %s\n", mystery, d, *answersFile)
+		return
+	default:
+		fmt.Println("unknown mode", *mode)
+		return
+	}
+
+	mysteries, guessables, err := loadDicts(wordLen, alpha)
+	if err == nil {
+		err = resumed.verify(mysteries)
+	}
 	if err != nil {
-		fmt.Println()
-		fmt.Println("******** ERROR ********")
-		fmt.Println()
 		fmt.Println(err)
 		return
 	}
+
+	// The game never repeats an answer. A resumed session leaves out the
+	// same ones it did when it was saved.
+	answers, ok := resumed.excluded()
+	if !ok {
+		answers, err = loadAnswers(*answersFile, alpha)
+		if err != nil {
+			fmt.Println(err)
+			return
+		}
+	}
+	loaded := mysteries
+	mysteries = excludeAnswers(mysteries, answers)
+	if len(answers) > 0 {
+		fmt.Printf("Excluded %d past answers\n\n", len(loaded)-len(mysteries))
+	}
+
+	if book != nil && bookFor(book, loaded, mysteries, mysteries, *strategy) == nil {
+		fmt.Printf("Not using the opening book %s: it was made for other words or another strategy, feedback rule, -probes or -preferCandidates\n\n", *bookFile)
+	}
+
+	h := newHistory(guessWords, guessMasks)
+
+	// If there are no guesses, just find the set of matches
+	if len(h) == 0 {
+		err = withRelax(*relax, mysteries, guessables, func(pool []string) error {
+			return crack(pool, guessables, masks, mystery)
+		})
+		if err != nil {
+			fmt.Println(err)
+			return
+		}
+	} else {
+		// There is a guess. Start solving.
+		err = withRelax(*relax, mysteries, guessables, func(pool []string) error {
+			return solveOne(pool, guessables, masks, h, mystery, *strategy, bookFor(book, loaded, mysteries, pool, *strategy))
+		})
+		if err != nil {
+			fmt.Println()
+			fmt.Println("******** ERROR ********")
+			fmt.Println()
+			fmt.Println(err)
+			return
+		}
+	}
+
+	if *saveFile != "" {
+		s := session{
+			Dict:       alpha.dict,
+			DictHash:   dictHash(loaded),
+			Guessables: *guessFile,
+			Lang:       *lang,
+			Alphabet:   *letters,
+			Feedback:   *rule,
+			Strategy:   *strategy,
+			Length:     wordLen,
+			Relax:      *relax,
+			Excluded:   []string{},
+			Colorbars:  []string{},
+			Guesses:    []string{},
+		}
+		remaining := map[string]bool{}
+		for _, word := range mysteries {
+			remaining[word] = true
+		}
+		for _, word := range loaded {
+			if !remaining[word] {
+				s.Excluded = append(s.Excluded, word)
+			}
+		}
+		if given {
+			s.Colorbars = masks
+		}
+		for _, t := range h {
+			s.Guesses = append(s.Guesses, t.String())
+		}
+		err = saveSession(s, *saveFile)
+		if err != nil {
+			fmt.Println(err)
+			return
+		}
+		fmt.Println("Saved session to", *saveFile)
+	}
 }
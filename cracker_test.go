@@ -1,7 +1,10 @@
 package main
 
 import (
+	"errors"
+	"strings"
 	"testing"
+	"unicode/utf8"
 )
 
 func equal(a, b []string) bool {
@@ -18,7 +21,7 @@ func equal(a, b []string) bool {
 	return true
 }
 
-func equalByte(a, b []byte) bool {
+func equalRune(a, b []rune) bool {
 	if len(a) != len(b) {
 		return false
 	}
@@ -87,19 +90,61 @@ func TestValidMask(t *testing.T) {
 	}
 }
 
+func TestWordLength(t *testing.T) {
+	testCases := []struct {
+		length      int
+		masks       []string
+		words       []string
+		wordMasks   []string
+		expected    int
+		expectError bool
+	}{
+		{0, []string{}, []string{}, []string{}, defaultWordLen, false},
+		{6, []string{}, []string{}, []string{}, 6, false},
+		{0, []string{"bbyy"}, []string{}, []string{}, 4, false},
+		{0, []string{}, []string{"crane"}, []string{"bbyyg"}, 5, false},
+		{0, []string{"bbyyb", "gggbb"}, []string{"crane"}, []string{"bbyyg"}, 5, false},
+		{4, []string{"bbyyb"}, []string{}, []string{}, 0, true},
+		{0, []string{"bbyy"}, []string{"crane"}, []string{"bbyyg"}, 0, true},
+		{0, []string{"bbyyb", "gggb"}, []string{}, []string{}, 0, true},
+		{8, []string{}, []string{"absolute"}, []string{"bbbbbbbb"}, 8, false},
+		{-3, []string{}, []string{}, []string{}, 0, true},
+		{-5, []string{}, []string{"crane"}, []string{"bbyyg"}, 0, true},
+	}
+
+	for _, testCase := range testCases {
+		answer, err := wordLength(testCase.length, testCase.masks, testCase.words, testCase.wordMasks)
+		if answer != testCase.expected {
+			t.Errorf("ERROR: For %d %v %v %v expected %d, got %d", testCase.length, testCase.masks, testCase.words, testCase.wordMasks, testCase.expected, answer)
+		}
+		if testCase.expectError && err == nil {
+			t.Errorf("ERROR: For %d %v %v %v expected error:<something>, got error:%v", testCase.length, testCase.masks, testCase.words, testCase.wordMasks, err)
+		}
+		if !testCase.expectError && err != nil {
+			t.Errorf("ERROR: For %d %v %v %v expected error:nil, got error:%v", testCase.length, testCase.masks, testCase.words, testCase.wordMasks, err)
+		}
+	}
+}
+
 func TestUnpackMasks(t *testing.T) {
 	testCases := []struct {
 		m           string
 		expected    []string
 		expectError bool
 	}{
-		{"", []string{""}, false},
+		{"", []string{}, false},
 		{"bbbyy", []string{"bbbyy"}, false},
 		{"bbbyy,gy,gyybb,gbygb,ggbgg", []string{}, true},
 		{"bbbyy,gybbbbb,gyybb,gbygb,ggbgg", []string{}, true},
 		{"bbbyy,gybbb,gyybb,gbygb,ggbgg,asdff", []string{}, true},
 		{"bbbyy,gybbb,gyybb,gbygb,ggbgg", []string{"bbbyy", "gbygb", "ggbgg", "gybbb", "gyybb"}, false},
 		{"bb,gy,gg", []string{"bb", "gg", "gy"}, false},
+		{"BBBYY, gybbb;GYYBB\ngbygb ", []string{"bbbyy", "gbygb", "gybbb", "gyybb"}, false},
+		{"bbbyy,,gybbb,", []string{"bbbyy", "gybbb"}, false},
+		{"..-yx,🟩⬛⬜🟨🟨", []string{"bbbyb", "gbbyy"}, false},
+		{"🟩️⬛️⬛️🟨️🟨️", []string{"gbbyy"}, false},
+		{"🟧🟦⬛⬛⬛", []string{"gybbb"}, false},
+		{"bbzyy,gybqb", []string{}, true},
 	}
 
 	for _, testCase := range testCases {
@@ -130,10 +175,18 @@ func TestUnpackGuessed(t *testing.T) {
 		{"bbbyy,gybbb,gyybb,gbygb,ggbgg,asdff", []string{}, []string{}, true},
 		{"moist/bbbyy,house/gybbb,walks/gyybb", []string{"moist", "house", "walks"}, []string{"bbbyy", "gybbb", "gyybb"}, false},
 		{"to/bb,of/gy,is/gg", []string{"to", "of", "is"}, []string{"bb", "gy", "gg"}, false},
+		{"Café/bbbg,it's/bbb", []string{"cafe", "its"}, []string{"bbbg", "bbb"}, false},
+		{"Café/bbbbg", []string{}, []string{}, true},
+		{"CRANE/GYBBB, moist/..-xy", []string{"crane", "moist"}, []string{"gybbb", "bbbby"}, false},
+		{"crane / gybbb;moist:bbbby  house=🟩⬛⬛⬛🟨", []string{"crane", "moist", "house"}, []string{"gybbb", "bbbby", "gbbby"}, false},
+		{"crane/gybbb,", []string{"crane"}, []string{"gybbb"}, false},
+		{"crane/gybbb,moist", []string{}, []string{}, true},
+		{"crane/gy/bbb", []string{}, []string{}, true},
+		{" , ", []string{}, []string{}, true},
 	}
 
 	for _, testCase := range testCases {
-		answer1, answer2, err := unpackGuessed(testCase.g)
+		answer1, answer2, err := unpackGuessed(testCase.g, alphabets["en"])
 		if !equal(answer1, testCase.expected1) {
 			t.Errorf("ERROR: For '%s' expected %v %v, got %v %v", testCase.g, testCase.expected1, testCase.expected2, answer1, answer2)
 		}
@@ -151,22 +204,23 @@ func TestUnpackGuessed(t *testing.T) {
 
 func TestReplace(t *testing.T) {
 	testCases := []struct {
-		w        []byte
-		a        byte
-		b        byte
-		expected []byte
+		w        []rune
+		a        rune
+		b        rune
+		expected []rune
 	}{
-		{[]byte{}, ' ', '_', []byte{}},
-		{[]byte{'a', 'b'}, '_', 'A', []byte{'a', 'b'}},
-		{[]byte{'a', 'b'}, 'a', 'c', []byte{'c', 'b'}},
-		{[]byte{'a', 'b'}, 'b', '4', []byte{'a', '4'}},
+		{[]rune{}, ' ', '_', []rune{}},
+		{[]rune{'a', 'b'}, '_', 'A', []rune{'a', 'b'}},
+		{[]rune{'a', 'b'}, 'a', 'c', []rune{'c', 'b'}},
+		{[]rune{'a', 'b'}, 'b', '4', []rune{'a', '4'}},
+		{[]rune{'n', 'ñ', 'ñ'}, 'ñ', '_', []rune{'n', '_', 'ñ'}},
 	}
 
 	for _, testCase := range testCases {
-		answer := make([]byte, len(testCase.w))
+		answer := make([]rune, len(testCase.w))
 		copy(answer, testCase.w)
 		replace(answer, testCase.a, testCase.b)
-		if !equalByte(answer, testCase.expected) {
+		if !equalRune(answer, testCase.expected) {
 			t.Errorf("ERROR: For %v %c %c expected %v, got %v", testCase.w, testCase.a, testCase.b, testCase.expected, answer)
 		}
 	}
@@ -174,14 +228,16 @@ func TestReplace(t *testing.T) {
 
 func TestContains(t *testing.T) {
 	testCases := []struct {
-		w        []byte
-		b        byte
+		w        []rune
+		b        rune
 		expected bool
 	}{
-		{[]byte{}, ' ', false},
-		{[]byte{'a', 'b'}, '_', false},
-		{[]byte{'a', 'b'}, 'a', true},
-		{[]byte{'a', 'b'}, 'b', true},
+		{[]rune{}, ' ', false},
+		{[]rune{'a', 'b'}, '_', false},
+		{[]rune{'a', 'b'}, 'a', true},
+		{[]rune{'a', 'b'}, 'b', true},
+		{[]rune{'n', 'o'}, 'ñ', false},
+		{[]rune{'ñ', 'o'}, 'ñ', true},
 	}
 
 	for _, testCase := range testCases {
@@ -218,6 +274,16 @@ func TestMatchSingleWord(t *testing.T) {
 		{"pha", "bby", "zzp", true},
 		{"those", "gbygb", "tress", true},
 		{"those", "gbygb", "trest", true},
+		{"aahs", "bbyy", "hish", false},
+		{"aahs", "ybyb", "hish", true},
+		{"niño", "ggbg", "nino", true},
+		{"niño", "gggg", "nino", false},
+		{"niño", "bbgb", "sañu", true},
+		{"niño", "bbyb", "sañu", false},
+		{"straße", "bybybg", "grüsse", true},
+		{"straße", "bbbbbg", "grüsse", false},
+		{"größe", "gybgg", "grüße", false},
+		{"größe", "ggbgg", "grüße", true},
 	}
 
 	for _, testCase := range testCases {
@@ -281,6 +347,9 @@ func TestMakeMask(t *testing.T) {
 		{"abc", "aaa", "gbb"},
 		{"abc", "cab", "yyy"},
 		{"apple", "house", "bbbbg"},
+		{"niño", "nino", "ggbg"},
+		{"niño", "ñame", "ybbb"},
+		{"größe", "grüße", "ggbgg"},
 	}
 
 	for _, testCase := range testCases {
@@ -291,16 +360,29 @@ func TestMakeMask(t *testing.T) {
 	}
 }
 
+// guessedWords returns a history of the given guesses, ignoring their masks
+func guessedWords(words ...string) history {
+	h := history{}
+
+	for _, word := range words {
+		h = append(h, turn{word: word})
+	}
+
+	return h
+}
+
 func TestFindMaxScore(t *testing.T) {
 	testCases := []struct {
 		s        []score
-		g        string
+		g        history
 		expected score
 	}{
-		{[]score{}, "abc", score{-1, ""}},
-		{[]score{{2, "aaa"}}, "abc", score{2, "aaa"}},
-		{[]score{{2, "abc"}}, "abc", score{-1, ""}},
-		{[]score{{2, "aaa"}, {5, "abc"}}, "abc", score{2, "aaa"}},
+		{[]score{}, guessedWords("abc"), score{-1, ""}},
+		{[]score{{2, "aaa"}}, guessedWords("abc"), score{2, "aaa"}},
+		{[]score{{2, "abc"}}, guessedWords("abc"), score{-1, ""}},
+		{[]score{{2, "aaa"}, {5, "abc"}}, guessedWords("abc"), score{2, "aaa"}},
+		{[]score{{2, "aaa"}, {5, "eat"}}, guessedWords("great"), score{5, "eat"}},
+		{[]score{{2, "aaa"}, {5, "eat"}}, guessedWords("gr", "eat"), score{2, "aaa"}},
 	}
 
 	for _, testCase := range testCases {
@@ -314,14 +396,15 @@ func TestFindMaxScore(t *testing.T) {
 func TestSuggestGuessLetterrFreq(t *testing.T) {
 	testCases := []struct {
 		m        []string
-		g        string
+		g        history
 		expected string
 	}{
-		{[]string{""}, "", ""},
-		{[]string{"abc"}, "", "abc"},
-		{[]string{"abc"}, "abc", ""},
-		{[]string{"abc", "def"}, "abc", "def"},
-		{[]string{"abc", "def"}, "def", "abc"},
+		{[]string{""}, guessedWords(), ""},
+		{[]string{"abc"}, guessedWords(), "abc"},
+		{[]string{"abc"}, guessedWords("abc"), ""},
+		{[]string{"abc", "def"}, guessedWords("abc"), "def"},
+		{[]string{"abc", "def"}, guessedWords("def"), "abc"},
+		{[]string{"bc", "de"}, guessedWords("abc", "def"), "bc"},
 	}
 
 	for _, testCase := range testCases {
@@ -384,3 +467,229 @@ func TestPruneGuessables(t *testing.T) {
 
 // Word #17 - shire
 // gggbg,bbbyg,ggbyb,ybyyg,ybyyb,bgybb,bgbyg,bbbbg,bgbbg,bgbyg,ggbgg,byggb,byyyy,gbggg,bbbyb,bybby,bybyy,gggyy,bbbyy,bbbbb,bggyb
+
+func FuzzValidMask(f *testing.F) {
+	for _, seed := range []struct {
+		m   string
+		len int
+	}{
+		{"", 0}, {"b", 1}, {"y", 1}, {"g", 1}, {"x", 1}, {"bbyyg", 5}, {"bbyyg", 3},
+	} {
+		f.Add(seed.m, seed.len)
+	}
+
+	f.Fuzz(func(t *testing.T, m string, length int) {
+		ok, err := validMask(m, length)
+		if ok != (err == nil) {
+			t.Fatalf("ERROR: For '%s' %d got %t and error:%v", m, length, ok, err)
+		}
+		if !ok {
+			return
+		}
+		if len(m) != length || strings.Trim(m, "gyb") != "" {
+			t.Errorf("ERROR: For '%s' %d expected it to be rejected", m, length)
+		}
+	})
+}
+
+func FuzzUnpackMasks(f *testing.F) {
+	for _, seed := range []string{
+		"", "bbbyy", "bbbyy,gy,gyybb,gbygb,ggbgg", "bbbyy,gybbbbb,gyybb,gbygb,ggbgg",
+		"bbbyy,gybbb,gyybb,gbygb,ggbgg,asdff", "bbbyy,gybbb,gyybb,gbygb,ggbgg", "bb,gy,gg",
+		"bbbyy,", ",bbbyy", "BBBYY",
+	} {
+		f.Add(seed)
+	}
+
+	f.Fuzz(func(t *testing.T, s string) {
+		masks, err := unpackMasks(s)
+		if err != nil {
+			if masks != nil {
+				t.Errorf("ERROR: For '%s' expected no masks with error:%v, got %v", s, err, masks)
+			}
+			return
+		}
+		for i, m := range masks {
+			if ok, err := validMask(m, len(masks[0])); !ok {
+				t.Errorf("ERROR: For '%s' got invalid mask '%s': %v", s, m, err)
+			}
+			if i > 0 && masks[i-1] >= m {
+				t.Errorf("ERROR: For '%s' expected sorted unique masks, got %v", s, masks)
+			}
+		}
+	})
+}
+
+func FuzzUnpackGuessed(f *testing.F) {
+	for _, seed := range []string{
+		"", "bbbyy", "bbbyy,gy,gyybb,gbygb,ggbgg", "moist/bbbyy,house/gybbb,walks/gyybb",
+		"to/bb,of/gy,is/gg", "Café/bbbg,it's/bbb", "Café/bbbbg", "crane/bbbbb,", "a/b/c", "'/",
+	} {
+		f.Add(seed)
+	}
+
+	f.Fuzz(func(t *testing.T, s string) {
+		words, masks, err := unpackGuessed(s, alphabets["en"])
+		if err != nil {
+			return
+		}
+		if len(words) != len(masks) || len(words) == 0 {
+			t.Fatalf("ERROR: For '%s' got %v %v", s, words, masks)
+		}
+		for i, word := range words {
+			if word == "" || alphabets["en"].normalize(word) != word {
+				t.Errorf("ERROR: For '%s' got unnormalized word '%s'", s, word)
+			}
+			if ok, err := validMask(masks[i], utf8.RuneCountInString(word)); !ok {
+				t.Errorf("ERROR: For '%s' got invalid mask '%s' for '%s': %v", s, masks[i], word, err)
+			}
+		}
+	})
+}
+
+func TestEmptiedBy(t *testing.T) {
+	testCases := []struct {
+		m        []string
+		g        []string
+		masks    []string
+		expected string
+	}{
+		{[]string{"foo"}, []string{"foo"}, []string{}, ""},
+		{[]string{"foo"}, []string{"foo"}, []string{"ggg"}, ""},
+		{[]string{"foo"}, []string{"foo"}, []string{"ggg", "bbb", "yyy"}, "bbb"},
+		{[]string{"foo", "bar"}, []string{"foo", "bar"}, []string{"bbb", "ggg"}, ""},
+		{[]string{"foo", "bar"}, []string{"foo"}, []string{"ggg", "bbb"}, "bbb"},
+	}
+
+	for _, testCase := range testCases {
+		answer := emptiedBy(testCase.m, testCase.g, testCase.masks)
+		if answer != testCase.expected {
+			t.Errorf("ERROR: For %v/%v/%v expected '%s', got '%s'", testCase.m, testCase.g, testCase.masks, testCase.expected, answer)
+		}
+	}
+}
+
+func TestNoCandidates(t *testing.T) {
+	words := []string{"abc", "abd"}
+
+	err := crack(words, words, []string{"ggg", "bbb"}, "")
+	if !errors.Is(err, errNoCandidates) || !strings.Contains(err.Error(), "bbb") {
+		t.Errorf("ERROR: For crack expected no candidates because of bbb, got %v", err)
+	}
+
+	err = crack([]string{}, words, []string{"ggg"}, "")
+	if !errors.Is(err, errNoCandidates) {
+		t.Errorf("ERROR: For crack with no mysteries expected no candidates, got %v", err)
+	}
+
+	err = solveOne(words, words, []string{"bbb"}, history{{"abc", "ggb"}}, "", "letterFreq", nil)
+	if !errors.Is(err, errNoCandidates) {
+		t.Errorf("ERROR: For solveOne with no matching colorbar expected no candidates, got %v", err)
+	}
+
+	err = solveOne(words, words, []string{"ggg"}, history{{"abc", "bbb"}}, "", "letterFreq", nil)
+	if !errors.Is(err, errNoCandidates) {
+		t.Errorf("ERROR: For solveOne with inconsistent guesses expected no candidates, got %v", err)
+	}
+
+	err = solveOne(words, words, []string{"ggg"}, history{{"abd", "ggb"}, {"abc", "ggg"}}, "", "letterFreq", nil)
+	if !errors.Is(err, errNoCandidates) {
+		t.Errorf("ERROR: For solveOne with every candidate guessed expected no candidates, got %v", err)
+	}
+}
+
+func TestWithRelax(t *testing.T) {
+	mysteries := []string{"abc", "abd"}
+	guessables := []string{"abc", "abd", "xyz", "zyx"}
+
+	// Only xyz and zyx give ygy for some guess, and only xyz fits abc/bbb
+	crackWith := func(masks []string) func(pool []string) error {
+		return func(pool []string) error {
+			return crack(pool, guessables, masks, "")
+		}
+	}
+	solveWith := func(h history) func(pool []string) error {
+		return func(pool []string) error {
+			return solveOne(pool, guessables, []string{}, h, "", "letterFreq", nil)
+		}
+	}
+
+	testCases := []struct {
+		name     string
+		solve    func(pool []string) error
+		relax    bool
+		expected bool
+	}{
+		{"crack", crackWith([]string{"ygy"}), false, false},
+		{"crack", crackWith([]string{"ygy"}), true, true},
+		{"solveOne", solveWith(history{{"abc", "bbb"}}), false, false},
+		{"solveOne", solveWith(history{{"abc", "bbb"}}), true, true},
+		// Nothing fits even when every guessable word may be the mystery
+		{"solveOne", solveWith(history{{"abc", "bbb"}, {"xyz", "bbb"}}), true, false},
+	}
+
+	for _, testCase := range testCases {
+		err := withRelax(testCase.relax, mysteries, guessables, testCase.solve)
+		if (err == nil) != testCase.expected || (err != nil && !errors.Is(err, errNoCandidates)) {
+			t.Errorf("ERROR: For %s with relax %t expected success %t, got %v", testCase.name, testCase.relax, testCase.expected, err)
+		}
+	}
+
+	// There is nothing to relax to when every guessable word is already a mystery
+	err := withRelax(true, guessables, guessables, solveWith(history{{"abc", "bbb"}, {"xyz", "bbb"}}))
+	if !errors.Is(err, errNoCandidates) || !strings.Contains(err.Error(), "relaxing would add no words") {
+		t.Errorf("ERROR: expected relaxing to add no words, got %v", err)
+	}
+
+	other := errors.New("other")
+	if err := withRelax(true, mysteries, guessables, func([]string) error { return other }); err != other {
+		t.Errorf("ERROR: With relax expected other errors to pass through, got %v", err)
+	}
+}
+
+func TestLoadDicts(t *testing.T) {
+	es := alphabets["es"]
+	es.dict = "testdata/es.dict"
+	accents := alphabets["es-accents"]
+	accents.dict = "testdata/es.dict"
+
+	testCases := []struct {
+		alpha    alphabet
+		l        int
+		expected []string
+	}{
+		{es, 4, []string{"gato", "niño", "papa"}},
+		{es, 5, []string{"arbol", "perro", "señor"}},
+		{accents, 4, []string{"gato", "niño", "papá"}},
+		{accents, 5, []string{"perro", "señor", "árbol"}},
+	}
+
+	for _, testCase := range testCases {
+		mysteries, guessables, err := loadDicts(testCase.l, testCase.alpha)
+		if err != nil || !equal(mysteries, testCase.expected) || !equal(guessables, testCase.expected) {
+			t.Errorf("ERROR: For %s %d expected %v, got %v %v %v", testCase.alpha.letters, testCase.l, testCase.expected, mysteries, guessables, err)
+		}
+	}
+
+	es.guesses = "testdata/es_guesses.dict"
+	mysteries, guessables, err := loadDicts(4, es)
+	if err != nil || !equal(mysteries, []string{"gato", "niño", "papa"}) || !equal(guessables, []string{"gata", "gato", "mesa", "niño", "papa"}) {
+		t.Errorf("ERROR: expected more guessables than mysteries, got %v %v %v", mysteries, guessables, err)
+	}
+	es.guesses = "testdata/missing.dict"
+	if _, _, err := loadDicts(4, es); err == nil {
+		t.Errorf("ERROR: expected an error for a missing guessables file")
+	}
+	es.guesses = ""
+
+	if _, _, err := loadDicts(9, es); err == nil {
+		t.Errorf("ERROR: expected an error for a word length with no words")
+	}
+	if _, _, err := loadDicts(5, alphabets["es"]); err == nil {
+		t.Errorf("ERROR: expected an error for a language with no dictionary")
+	}
+	es.dict = "testdata/missing.dict"
+	if _, _, err := loadDicts(5, es); err == nil {
+		t.Errorf("ERROR: expected an error for a missing dictionary")
+	}
+}
@@ -0,0 +1,273 @@
+package main
+
+import (
+	"fmt"
+	"strconv"
+)
+
+// feedbackRule is how a game colors the tiles of a guess. The mask method is
+// the one place a rule is implemented; makeMask, matchSingleWord and
+// pruneGuessables are all derived from it so they cannot disagree.
+type feedbackRule interface {
+	// name returns the name the rule is selected by
+	name() string
+	// mask returns the colorbar shown for guess when word is the mystery
+	mask(word, guess string) string
+	// valid returns an error if mask cannot be given for a word of length letters
+	valid(mask string, length int) error
+	// length returns the word length implied by mask, or 0 if it implies none
+	length(mask string) int
+}
+
+// feedbackRules maps rule names to the rules
+var feedbackRules = map[string]feedbackRule{
+	"standard":    standardRule{},
+	"every":       everyRule{},
+	"leftToRight": leftToRightRule{},
+	"jotto":       jottoRule{},
+	"mastermind":  mastermindRule{},
+}
+
+// feedback is the rule used by makeMask, matchSingleWord and pruneGuessables
+var feedback feedbackRule = standardRule{}
+
+// spoken marks a letter of the mystery word that has already been matched. It
+// is not a valid rune, so it can never be confused with a letter of a guess.
+const spoken rune = -1
+
+// solvedMask returns the mask given when a word of length letters is guessed correctly
+func solvedMask(length int) string {
+	word := string(make([]rune, length))
+	return feedback.mask(word, word)
+}
+
+// colorbar is embedded by the rules whose masks are a g, y or b per letter
+type colorbar struct{}
+
+// valid returns an error if mask is not a colorbar for a word of length letters
+func (colorbar) valid(mask string, length int) error {
+	if len(mask) != length {
+		return fmt.Errorf("masks must all be of the same length %s", mask)
+	}
+
+	for _, val := range mask {
+		switch val {
+		case 'g':
+		case 'y':
+		case 'b':
+		default:
+			return fmt.Errorf("masks must contain only g, y, or b %s %c", mask, val)
+		}
+	}
+
+	return nil
+}
+
+// length returns the word length implied by mask
+func (colorbar) length(mask string) int {
+	return len(mask)
+}
+
+// standardRule is the official Wordle rule
+type standardRule struct {
+	colorbar
+}
+
+// name returns the name the rule is selected by
+func (standardRule) name() string {
+	return "standard"
+}
+
+// mask returns the byg mask for the given guess and given mystery word. Greens
+// are marked first, then yellows are consumed from left to right.
+func (standardRule) mask(word, guess string) string {
+	w := []rune(word)
+	g := []rune(guess)
+	m := make([]byte, len(w))
+
+	// g
+	for i := range w {
+		if w[i] != g[i] {
+			continue
+		}
+		m[i] = 'g'
+		w[i] = spoken
+	}
+
+	// y and b
+	for i := range w {
+		if m[i] != 0 {
+			continue
+		}
+
+		if contains(w, g[i]) {
+			m[i] = 'y'
+			replace(w, g[i], spoken)
+			continue
+		}
+
+		m[i] = 'b'
+	}
+
+	return string(m)
+}
+
+// everyRule marks every non-green occurrence of a letter that is in the
+// mystery word yellow, no matter how many times it appears
+type everyRule struct {
+	colorbar
+}
+
+// name returns the name the rule is selected by
+func (everyRule) name() string {
+	return "every"
+}
+
+// mask returns the byg mask for the given guess and given mystery word
+func (everyRule) mask(word, guess string) string {
+	w := []rune(word)
+	g := []rune(guess)
+	m := make([]byte, len(w))
+
+	for i := range w {
+		switch {
+		case w[i] == g[i]:
+			m[i] = 'g'
+		case contains(w, g[i]):
+			m[i] = 'y'
+		default:
+			m[i] = 'b'
+		}
+	}
+
+	return string(m)
+}
+
+// leftToRightRule colors the tiles in a single pass from left to right, so a
+// yellow can use up a letter that a later tile would have matched exactly
+type leftToRightRule struct {
+	colorbar
+}
+
+// name returns the name the rule is selected by
+func (leftToRightRule) name() string {
+	return "leftToRight"
+}
+
+// mask returns the byg mask for the given guess and given mystery word
+func (leftToRightRule) mask(word, guess string) string {
+	w := []rune(word)
+	g := []rune(guess)
+	m := make([]byte, len(w))
+
+	// Letters of word that have not yet been "spoken for"
+	left := make([]rune, len(w))
+	copy(left, w)
+
+	for i := range w {
+		if w[i] == g[i] {
+			m[i] = 'g'
+			if left[i] == g[i] {
+				left[i] = spoken
+			} else {
+				replace(left, g[i], spoken)
+			}
+			continue
+		}
+
+		if contains(left, g[i]) {
+			m[i] = 'y'
+			replace(left, g[i], spoken)
+			continue
+		}
+
+		m[i] = 'b'
+	}
+
+	return string(m)
+}
+
+// counts returns the number of exact and misplaced letters of guess when word
+// is the mystery, using the standard rule for repeated letters
+func counts(word, guess string) (int, int) {
+	exact, misplaced := 0, 0
+
+	for _, m := range (standardRule{}).mask(word, guess) {
+		switch m {
+		case 'g':
+			exact++
+		case 'y':
+			misplaced++
+		}
+	}
+
+	return exact, misplaced
+}
+
+// jottoRule gives only the number of letters guess has in common with the
+// mystery word, e.g., 3
+type jottoRule struct{}
+
+// name returns the name the rule is selected by
+func (jottoRule) name() string {
+	return "jotto"
+}
+
+// mask returns the count of letters in common for the given guess and given mystery word
+func (jottoRule) mask(word, guess string) string {
+	exact, misplaced := counts(word, guess)
+	return strconv.Itoa(exact + misplaced)
+}
+
+// valid returns an error if mask is not a count for a word of length letters
+func (jottoRule) valid(mask string, length int) error {
+	n, err := strconv.Atoi(mask)
+	if err != nil || strconv.Itoa(n) != mask {
+		return fmt.Errorf("jotto masks must be a count of common letters %s", mask)
+	}
+	if n < 0 || (length > 0 && n > length) {
+		return fmt.Errorf("jotto masks must be between 0 and %d %s", length, mask)
+	}
+
+	return nil
+}
+
+// length returns 0, since counts do not imply a word length
+func (jottoRule) length(mask string) int {
+	return 0
+}
+
+// mastermindRule gives the number of exact and misplaced letters without
+// saying which they are, e.g., 2g1y
+type mastermindRule struct{}
+
+// name returns the name the rule is selected by
+func (mastermindRule) name() string {
+	return "mastermind"
+}
+
+// mask returns the exact/misplaced counts for the given guess and given mystery word
+func (mastermindRule) mask(word, guess string) string {
+	exact, misplaced := counts(word, guess)
+	return fmt.Sprintf("%dg%dy", exact, misplaced)
+}
+
+// valid returns an error if mask is not a pair of counts for a word of length letters
+func (mastermindRule) valid(mask string, length int) error {
+	exact, misplaced := 0, 0
+
+	n, err := fmt.Sscanf(mask, "%dg%dy", &exact, &misplaced)
+	if err != nil || n != 2 || fmt.Sprintf("%dg%dy", exact, misplaced) != mask {
+		return fmt.Errorf("mastermind masks must be of the form <exact>g<misplaced>y %s", mask)
+	}
+	if exact < 0 || misplaced < 0 || (length > 0 && exact+misplaced > length) {
+		return fmt.Errorf("mastermind masks must count between 0 and %d letters %s", length, mask)
+	}
+
+	return nil
+}
+
+// length returns 0, since counts do not imply a word length
+func (mastermindRule) length(mask string) int {
+	return 0
+}
@@ -0,0 +1,340 @@
+package main
+
+import (
+	"os"
+	"strings"
+	"testing"
+	"unicode/utf8"
+
+	"github.com/erikbryant/dictionaries"
+)
+
+func TestFeedbackRuleMask(t *testing.T) {
+	testCases := []struct {
+		rule     string
+		w        string
+		g        string
+		expected string
+	}{
+		{"standard", "abc", "bbx", "bgb"},
+		{"standard", "apple", "pappy", "yygbb"},
+		{"standard", "those", "tress", "gbygb"},
+		{"every", "", "", ""},
+		{"every", "abc", "ddd", "bbb"},
+		{"every", "abc", "aaa", "gyy"},
+		{"every", "abc", "bbx", "ygb"},
+		{"every", "apple", "pappy", "yygyb"},
+		{"every", "those", "tress", "gbygy"},
+		{"leftToRight", "", "", ""},
+		{"leftToRight", "abc", "ddd", "bbb"},
+		{"leftToRight", "abc", "aaa", "gbb"},
+		{"leftToRight", "abc", "bbx", "ygb"},
+		{"leftToRight", "apple", "pappy", "yygbb"},
+		{"leftToRight", "those", "tress", "gbygb"},
+		{"leftToRight", "those", "ssoes", "ybgyb"},
+		{"jotto", "", "", "0"},
+		{"jotto", "abc", "ddd", "0"},
+		{"jotto", "abc", "aaa", "1"},
+		{"jotto", "abc", "cab", "3"},
+		{"jotto", "apple", "pappy", "3"},
+		{"mastermind", "", "", "0g0y"},
+		{"mastermind", "abc", "ddd", "0g0y"},
+		{"mastermind", "abc", "aaa", "1g0y"},
+		{"mastermind", "abc", "cab", "0g3y"},
+		{"mastermind", "apple", "pappy", "1g2y"},
+	}
+
+	for _, testCase := range testCases {
+		answer := feedbackRules[testCase.rule].mask(testCase.w, testCase.g)
+		if answer != testCase.expected {
+			t.Errorf("ERROR: For %s %s %s expected %s, got %s", testCase.rule, testCase.w, testCase.g, testCase.expected, answer)
+		}
+	}
+}
+
+// consistencyWords are full of repeated letters, which is where rules go wrong
+var consistencyWords = []string{
+	"abbey", "apple", "pappy", "those", "tress", "ssoes", "geese", "eerie",
+	"llama", "alarm", "sassy", "asses", "kayak", "yakka", "otter", "toter",
+	"crane", "nacre", "speed", "deeps", "mummy", "tummy", "error", "rower",
+}
+
+// standardOracle returns whether mask is the standard rule's colorbar for
+// guess against word, working from what the colorbar means rather than from how
+// makeMask builds it: a letter is green where it is in place, and otherwise the
+// leftmost of its copies in guess are yellow, as many as word has left over.
+func standardOracle(word, mask, guess string) bool {
+	w, m, g := []rune(word), []rune(mask), []rune(guess)
+	if len(w) != len(m) || len(w) != len(g) {
+		return false
+	}
+
+	left := map[rune]int{}
+	for i := range w {
+		if (m[i] == 'g') != (w[i] == g[i]) {
+			return false
+		}
+		if w[i] != g[i] {
+			left[w[i]]++
+		}
+	}
+
+	for i := range g {
+		if m[i] == 'g' {
+			continue
+		}
+		if left[g[i]] > 0 {
+			if m[i] != 'y' {
+				return false
+			}
+			left[g[i]]--
+			continue
+		}
+		if m[i] != 'b' {
+			return false
+		}
+	}
+
+	return true
+}
+
+// consistencyDict returns the words to check every pair of. The full
+// mystery dictionary is used when it is present and the tests are not short.
+func consistencyDict() []string {
+	if testing.Short() {
+		return consistencyWords
+	}
+
+	file := "../dictionaries/wordleMystery.dict"
+	if _, err := os.Stat(file); err != nil {
+		return consistencyWords
+	}
+
+	return dictionaries.LoadFile(file)
+}
+
+// allColorbars returns every possible g/y/b colorbar of the given length
+func allColorbars(length int) []string {
+	masks := []string{""}
+
+	for i := 0; i < length; i++ {
+		longer := []string{}
+		for _, mask := range masks {
+			longer = append(longer, mask+"g", mask+"y", mask+"b")
+		}
+		masks = longer
+	}
+
+	return masks
+}
+
+func TestMatchSingleWordConsistent(t *testing.T) {
+	defer func(r feedbackRule) { feedback = r }(feedback)
+
+	// The default rule is checked against the full dictionary...
+	feedback = standardRule{}
+	words := consistencyDict()
+	for _, w := range words {
+		for _, g := range words {
+			mask := makeMask(w, g)
+			if !standardOracle(w, mask, g) || !matchSingleWord(w, mask, g) {
+				t.Fatalf("ERROR: For %s/%s/%s expected match", w, mask, g)
+			}
+		}
+	}
+
+	// ...and against every colorbar
+	for _, w := range consistencyWords {
+		for _, g := range consistencyWords {
+			for _, mask := range allColorbars(len(w)) {
+				expected := standardOracle(w, mask, g)
+				if matchSingleWord(w, mask, g) != expected {
+					t.Errorf("ERROR: For %s/%s/%s expected match=%t", w, mask, g, expected)
+				}
+			}
+		}
+	}
+
+	for name, rule := range feedbackRules {
+		if rule.name() != name {
+			t.Errorf("ERROR: For %s expected name %s, got %s", name, name, rule.name())
+		}
+		feedback = rule
+		for _, w := range consistencyWords {
+			for _, g := range consistencyWords {
+				mask := makeMask(w, g)
+				if err := rule.valid(mask, len(w)); err != nil {
+					t.Errorf("ERROR: For %s %s/%s expected a valid mask, got %v", name, w, g, err)
+				}
+				if !matchSingleWord(w, mask, g) {
+					t.Errorf("ERROR: For %s %s/%s/%s expected match", name, w, mask, g)
+				}
+			}
+		}
+	}
+}
+
+func TestMatchSingleWordUnique(t *testing.T) {
+	defer func(r feedbackRule) { feedback = r }(feedback)
+
+	// Exactly one colorbar can describe any mystery/guess pair
+	for _, name := range []string{"standard", "every", "leftToRight"} {
+		feedback = feedbackRules[name]
+		for _, w := range consistencyWords {
+			for _, g := range consistencyWords {
+				matches := 0
+				for _, mask := range allColorbars(len(w)) {
+					if matchSingleWord(w, mask, g) {
+						matches++
+					}
+				}
+				if matches != 1 {
+					t.Errorf("ERROR: For %s %s/%s expected 1 matching colorbar, got %d", name, w, g, matches)
+				}
+			}
+		}
+	}
+}
+
+func TestFeedbackSelectsRule(t *testing.T) {
+	defer func(r feedbackRule) { feedback = r }(feedback)
+
+	feedback = everyRule{}
+	if makeMask("abc", "aaa") != "gyy" {
+		t.Errorf("ERROR: expected makeMask to use the every rule, got %s", makeMask("abc", "aaa"))
+	}
+	if !matchSingleWord("abc", "gyy", "aaa") {
+		t.Errorf("ERROR: expected matchSingleWord to use the every rule")
+	}
+
+	answer := pruneGuessables([]string{"abc", "acd", "xyz"}, "aaa", "gyy")
+	if !equal(answer, []string{"abc", "acd"}) {
+		t.Errorf("ERROR: expected pruneGuessables to use the every rule, got %v", answer)
+	}
+}
+
+func TestFeedbackRuleValid(t *testing.T) {
+	testCases := []struct {
+		rule     string
+		m        string
+		len      int
+		expected bool
+	}{
+		{"standard", "bbyyg", 5, true},
+		{"standard", "bbyyg", 4, false},
+		{"standard", "3", 5, false},
+		{"every", "bbxyg", 5, false},
+		{"jotto", "3", 5, true},
+		{"jotto", "0", 5, true},
+		{"jotto", "5", 5, true},
+		{"jotto", "6", 5, false},
+		{"jotto", "6", 0, true},
+		{"jotto", "-1", 5, false},
+		{"jotto", "03", 5, false},
+		{"jotto", "bbyyg", 5, false},
+		{"jotto", "", 5, false},
+		{"mastermind", "2g1y", 5, true},
+		{"mastermind", "0g0y", 5, true},
+		{"mastermind", "3g3y", 5, false},
+		{"mastermind", "3g3y", 0, true},
+		{"mastermind", "2g", 5, false},
+		{"mastermind", "2g1yy", 5, false},
+		{"mastermind", "2y1g", 5, false},
+		{"mastermind", "3", 5, false},
+	}
+
+	for _, testCase := range testCases {
+		err := feedbackRules[testCase.rule].valid(testCase.m, testCase.len)
+		if (err == nil) != testCase.expected {
+			t.Errorf("ERROR: For %s %s %d expected %t, got %v", testCase.rule, testCase.m, testCase.len, testCase.expected, err)
+		}
+	}
+}
+
+func TestSolvedMask(t *testing.T) {
+	defer func(r feedbackRule) { feedback = r }(feedback)
+
+	testCases := []struct {
+		rule     string
+		len      int
+		expected string
+	}{
+		{"standard", 0, ""},
+		{"standard", 5, "ggggg"},
+		{"every", 3, "ggg"},
+		{"jotto", 5, "5"},
+		{"mastermind", 4, "4g0y"},
+	}
+
+	for _, testCase := range testCases {
+		feedback = feedbackRules[testCase.rule]
+		answer := solvedMask(testCase.len)
+		if answer != testCase.expected {
+			t.Errorf("ERROR: For %s %d expected %s, got %s", testCase.rule, testCase.len, testCase.expected, answer)
+		}
+	}
+}
+
+func TestCountFeedbackPrunes(t *testing.T) {
+	defer func(r feedbackRule) { feedback = r }(feedback)
+
+	feedback = jottoRule{}
+	answer := pruneGuessables([]string{"abc", "cab", "abd", "xyz"}, "bca", "3")
+	if !equal(answer, []string{"abc", "cab"}) {
+		t.Errorf("ERROR: For jotto expected [abc cab], got %v", answer)
+	}
+
+	feedback = mastermindRule{}
+	answer = pruneGuessables([]string{"abc", "cab", "abd", "xyz"}, "abx", "2g0y")
+	if !equal(answer, []string{"abc", "abd"}) {
+		t.Errorf("ERROR: For mastermind expected [abc abd], got %v", answer)
+	}
+
+	length, err := wordLength(0, []string{"2g1y"}, []string{"crane"}, []string{"0g0y"})
+	if length != 5 || err != nil {
+		t.Errorf("ERROR: For mastermind expected 5 <nil>, got %d %v", length, err)
+	}
+	length, err = wordLength(4, []string{"2g3y"}, []string{}, []string{})
+	if err == nil {
+		t.Errorf("ERROR: For mastermind expected an error, got %d %v", length, err)
+	}
+}
+
+func FuzzMakeMask(f *testing.F) {
+	for _, seed := range []struct {
+		w string
+		g string
+	}{
+		{"", ""}, {"abc", "ddd"}, {"abc", "aaa"}, {"abc", "cab"}, {"apple", "house"},
+		{"niño", "nino"}, {"niño", "ñame"}, {"größe", "grüße"}, {"aahs", "hish"}, {"those", "tress"},
+	} {
+		f.Add(seed.w, seed.g)
+	}
+
+	f.Fuzz(func(t *testing.T, w, g string) {
+		length := utf8.RuneCountInString(w)
+		if length != utf8.RuneCountInString(g) {
+			return
+		}
+		wRunes, gRunes := []rune(w), []rune(g)
+
+		mask := makeMask(w, g)
+		if ok, err := validMask(mask, length); !ok {
+			t.Fatalf("ERROR: For %s/%s got invalid mask %s: %v", w, g, mask, err)
+		}
+		for i, m := range mask {
+			if (m == 'g') != (wRunes[i] == gRunes[i]) {
+				t.Errorf("ERROR: For %s/%s got %s, green in the wrong place", w, g, mask)
+			}
+			if m == 'y' && !strings.ContainsRune(w, gRunes[i]) {
+				t.Errorf("ERROR: For %s/%s got %s, yellow for a missing letter", w, g, mask)
+			}
+		}
+		if !matchSingleWord(w, mask, g) {
+			t.Errorf("ERROR: For %s/%s/%s expected match", w, mask, g)
+		}
+		if makeMask(w, w) != strings.Repeat("g", length) {
+			t.Errorf("ERROR: For %s/%s expected all green, got %s", w, w, makeMask(w, w))
+		}
+	})
+}
@@ -0,0 +1,161 @@
+package main
+
+import (
+	"fmt"
+	"io"
+	"math/rand"
+	"os"
+	"strings"
+	"time"
+	"unicode/utf8"
+
+	"github.com/erikbryant/dictionaries"
+)
+
+// The number of guesses a player gets before the game is lost
+const maxTurns = 6
+
+// player picks the next guess from the words that remain
+type player func(words []string, h history, rng *rand.Rand) string
+
+// shareTiles maps mask tiles to the emoji used when a grid is shared
+var shareTiles = map[rune]string{
+	'g': "🟩",
+	'y': "🟨",
+	'b': "⬛",
+}
+
+// playGrid returns the turns a player takes to find the mystery, stopping
+// after maxTurns
+func playGrid(mystery string, words []string, p player, rng *rand.Rand) history {
+	h := history{}
+
+	for len(h) < maxTurns {
+		guess := p(words, h, rng)
+		if guess == "" {
+			break
+		}
+
+		mask := makeMask(mystery, guess)
+		h = append(h, turn{guess, mask})
+		if guess == mystery {
+			break
+		}
+
+		words = pruneGuessables(words, guess, mask)
+	}
+
+	return h
+}
+
+// shareRow returns the mask as a row of a shared grid
+func shareRow(mask string) string {
+	row := ""
+
+	for _, r := range mask {
+		if tile, ok := shareTiles[r]; ok {
+			row += tile
+			continue
+		}
+		row += string(r)
+	}
+
+	return row
+}
+
+// shareText returns the grid as it would be shared, with a header line
+func shareText(id string, mystery string, h history) string {
+	score := "X"
+	if len(h) > 0 && h[len(h)-1].word == mystery {
+		score = fmt.Sprint(len(h))
+	}
+
+	rows := []string{fmt.Sprintf("Wordle %s %s/%d", id, score, maxTurns)}
+	for _, t := range h {
+		rows = append(rows, shareRow(t.mask))
+	}
+
+	return strings.Join(rows, "\n")
+}
+
+// generate writes days of synthetic puzzles to w in the batch form read by
+// -input. Each day has a mystery picked at random, unless one is given, and
+// the shared grids of the given number of players.
+func generate(w io.Writer, mysteries []string, mystery string, days, players int, p player, rng *rand.Rand) error {
+	if len(mysteries) == 0 {
+		return fmt.Errorf("no mystery words to generate puzzles from")
+	}
+
+	for day := 1; day <= days; day++ {
+		answer := mystery
+		if answer == "" {
+			answer = mysteries[rng.Intn(len(mysteries))]
+		}
+		id := fmt.Sprint(day)
+
+		_, err := fmt.Fprintf(w, "puzzle %s %s\n", id, answer)
+		if err != nil {
+			return err
+		}
+		for i := 0; i < players; i++ {
+			h := playGrid(answer, mysteries, p, rng)
+			_, err = fmt.Fprintf(w, "%s\n\n", shareText(id, answer, h))
+			if err != nil {
+				return err
+			}
+		}
+	}
+
+	return nil
+}
+
+// runGenerate writes synthetic puzzles to file, or to stdout if file is empty.
+// A seed of 0 picks one from the clock. The seed used is written first, as a
+// comment, so the puzzles can be generated again.
+func runGenerate(file string, wordLen int, alpha alphabet, mystery, spec string, days, players int, seed int64) error {
+	m, err := parsePlayer(spec, alpha)
+	if err != nil {
+		return err
+	}
+
+	if mystery != "" && utf8.RuneCountInString(mystery) != wordLen {
+		return fmt.Errorf("mystery %s does not have %d letters", mystery, wordLen)
+	}
+
+	mysteries, guessables, err := loadDicts(wordLen, alpha)
+	if err != nil {
+		return err
+	}
+	if mystery != "" && !dictionaries.ContainsWord(mysteries, mystery) {
+		return fmt.Errorf("mystery %s is not in the dictionary", mystery)
+	}
+	err = m.validOpener(wordLen, guessables)
+	if err != nil {
+		return err
+	}
+	known, err := m.vocabulary(guessables, wordLen, alpha)
+	if err != nil {
+		return err
+	}
+
+	if seed == 0 {
+		seed = time.Now().UnixNano()
+	}
+	rng := rand.New(rand.NewSource(seed))
+
+	w := os.Stdout
+	if file != "" {
+		w, err = os.Create(file)
+		if err != nil {
+			return err
+		}
+		defer w.Close()
+	}
+
+	_, err = fmt.Fprintf(w, "# seed %d, player %s\n", seed, spec)
+	if err != nil {
+		return err
+	}
+
+	return generate(w, mysteries, mystery, days, players, m.player(known), rng)
+}
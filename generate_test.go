@@ -0,0 +1,122 @@
+package main
+
+import (
+	"bytes"
+	"math/rand"
+	"path/filepath"
+	"testing"
+
+	"github.com/erikbryant/dictionaries"
+)
+
+func TestShareRow(t *testing.T) {
+	testCases := []struct {
+		mask     string
+		expected string
+	}{
+		{"", ""},
+		{"gyb", "🟩🟨⬛"},
+		{"bbbbb", "⬛⬛⬛⬛⬛"},
+		{"2g1y", "2🟩1🟨"},
+	}
+
+	for _, testCase := range testCases {
+		answer := shareRow(testCase.mask)
+		if answer != testCase.expected {
+			t.Errorf("ERROR: For %s expected %s, got %s", testCase.mask, testCase.expected, answer)
+		}
+	}
+}
+
+func TestShareText(t *testing.T) {
+	testCases := []struct {
+		h        history
+		expected string
+	}{
+		{history{{"dog", "bbb"}, {"cat", "ggg"}}, "Wordle 7 2/6\n⬛⬛⬛\n🟩🟩🟩"},
+		{history{{"dog", "bbb"}}, "Wordle 7 X/6\n⬛⬛⬛"},
+		{history{}, "Wordle 7 X/6"},
+	}
+
+	for _, testCase := range testCases {
+		answer := shareText("7", "cat", testCase.h)
+		if answer != testCase.expected {
+			t.Errorf("ERROR: For %v expected %q, got %q", testCase.h, testCase.expected, answer)
+		}
+	}
+}
+
+func TestPlayGrid(t *testing.T) {
+	for _, name := range []string{"random", "noisy", "expert", "letterFreq", "random,opener=dog,offList=0.5,hard"} {
+		m, err := parsePlayer(name, alphabets["en"])
+		if err != nil {
+			t.Errorf("ERROR: For %s expected a player, got %v", name, err)
+			continue
+		}
+		p := m.player(consistencyTestWords)
+		h := playGrid("tag", consistencyTestWords, p, rand.New(rand.NewSource(1)))
+		if len(h) == 0 || len(h) > maxTurns || h[len(h)-1].word != "tag" {
+			t.Errorf("ERROR: For %s expected to find tag, got %v", name, h)
+		}
+	}
+}
+
+func TestGenerate(t *testing.T) {
+	m, _ := parsePlayer("random", alphabets["en"])
+	p := m.player(consistencyTestWords)
+
+	var b bytes.Buffer
+	err := generate(&b, consistencyTestWords, "", 5, 3, p, rand.New(rand.NewSource(1)))
+	if err != nil {
+		t.Fatalf("ERROR: expected no error, got %v", err)
+	}
+
+	// The generated puzzles must read back, and crack must keep every answer
+	days, err := readBatch(&b, alphabets["en"])
+	if err != nil || len(days) != 5 {
+		t.Fatalf("ERROR: expected 5 days, got %d %v", len(days), err)
+	}
+	for _, day := range days {
+		if len(day.input.grids) != 3 {
+			t.Errorf("ERROR: For day %s expected 3 grids, got %v", day.id, day.input.grids)
+		}
+		r := crackDay(day, wordLists{consistencyTestWords, consistencyTestWords})
+		if !dictionaries.ContainsWord(r.candidates, day.answer) {
+			t.Errorf("ERROR: For day %s expected %s among %v", day.id, day.answer, r.candidates)
+		}
+	}
+
+	err = generate(&b, []string{}, "", 1, 1, p, rand.New(rand.NewSource(1)))
+	if err == nil {
+		t.Errorf("ERROR: expected an error with no mystery words")
+	}
+}
+
+func TestRunGenerateMystery(t *testing.T) {
+	es := alphabets["es"]
+	es.dict = "testdata/es.dict"
+	file := filepath.Join(t.TempDir(), "puzzles")
+
+	testCases := []struct {
+		mystery  string
+		spec     string
+		expected bool
+	}{
+		{"", "random", true},
+		{"perro", "random", true},
+		{"gato", "random", false},
+		{"canción", "random", false},
+		{"gatos", "random", false},
+		{"", "noisy,opener=perro", true},
+		{"", "noisy,opener=gato", false},
+		{"", "noisy,opener=perros", false},
+		{"", "noisy,opener=gatos", false},
+	}
+
+	for _, testCase := range testCases {
+		err := runGenerate(file, 5, es, testCase.mystery, testCase.spec, 1, 1, 1)
+		if (err == nil) != testCase.expected {
+			t.Errorf("ERROR: For %s %s expected success %t, got %v", testCase.mystery, testCase.spec, testCase.expected, err)
+		}
+	}
+}
@@ -0,0 +1,52 @@
+package main
+
+import (
+	"strings"
+)
+
+// turn is a word that was guessed and the mask the game gave back for it
+type turn struct {
+	word string
+	mask string
+}
+
+// history is the turns played so far, in order
+type history []turn
+
+// newHistory returns the history of the given guesses and their masks
+func newHistory(words, masks []string) history {
+	h := history{}
+
+	for i := range words {
+		h = append(h, turn{words[i], masks[i]})
+	}
+
+	return h
+}
+
+// guessed returns true if word has already been guessed
+func (h history) guessed(word string) bool {
+	for _, t := range h {
+		if t.word == word {
+			return true
+		}
+	}
+
+	return false
+}
+
+// String returns the history in the same guess/mask,... form as -guessed
+func (h history) String() string {
+	pairs := []string{}
+
+	for _, t := range h {
+		pairs = append(pairs, t.String())
+	}
+
+	return strings.Join(pairs, ",")
+}
+
+// String returns the turn in guess/mask form
+func (t turn) String() string {
+	return t.word + "/" + t.mask
+}
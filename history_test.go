@@ -0,0 +1,49 @@
+package main
+
+import (
+	"testing"
+)
+
+func TestNewHistory(t *testing.T) {
+	testCases := []struct {
+		words    []string
+		masks    []string
+		expected string
+	}{
+		{[]string{}, []string{}, ""},
+		{[]string{"crane"}, []string{"gybbb"}, "crane/gybbb"},
+		{[]string{"crane", "moist"}, []string{"gybbb", "bbbby"}, "crane/gybbb,moist/bbbby"},
+	}
+
+	for _, testCase := range testCases {
+		h := newHistory(testCase.words, testCase.masks)
+		if len(h) != len(testCase.words) {
+			t.Errorf("ERROR: For %v %v expected %d turns, got %d", testCase.words, testCase.masks, len(testCase.words), len(h))
+		}
+		if h.String() != testCase.expected {
+			t.Errorf("ERROR: For %v %v expected %s, got %s", testCase.words, testCase.masks, testCase.expected, h.String())
+		}
+	}
+}
+
+func TestHistoryGuessed(t *testing.T) {
+	testCases := []struct {
+		h        history
+		w        string
+		expected bool
+	}{
+		{history{}, "eat", false},
+		{history{{"great", "bbbbb"}}, "eat", false},
+		{history{{"great", "bbbbb"}}, "great", true},
+		{history{{"gr", "bb"}, {"eat", "bbb"}}, "eat", true},
+		{history{{"gr", "bb"}, {"eat", "bbb"}}, "great", false},
+		{history{{"abc", "bbb"}}, "", false},
+	}
+
+	for _, testCase := range testCases {
+		answer := testCase.h.guessed(testCase.w)
+		if answer != testCase.expected {
+			t.Errorf("ERROR: For %v %s expected %t, got %t", testCase.h, testCase.w, testCase.expected, answer)
+		}
+	}
+}
@@ -0,0 +1,127 @@
+package main
+
+import (
+	"bufio"
+	"fmt"
+	"io"
+	"os"
+	"strings"
+)
+
+// puzzleInput is the colorbars and guesses read from an input file
+type puzzleInput struct {
+	grids      [][]string // the colorbars of each player
+	guessWords []string
+	guessMasks []string
+	open       bool // whether the next colorbars belong to the last grid
+}
+
+// newPuzzleInput returns an empty puzzleInput
+func newPuzzleInput() puzzleInput {
+	return puzzleInput{grids: [][]string{}, guessWords: []string{}, guessMasks: []string{}}
+}
+
+// masks returns the colorbars of every player
+func (in puzzleInput) masks() []string {
+	masks := []string{}
+
+	for _, grid := range in.grids {
+		masks = append(masks, grid...)
+	}
+
+	return masks
+}
+
+// isShareHeader returns true if the line is the header of a shared grid, such
+// as "Wordle 1,234 4/6"
+func isShareHeader(line string) bool {
+	fields := strings.Fields(line)
+	return len(fields) > 0 && strings.ToLower(fields[0]) == "wordle"
+}
+
+// hasPair returns true if the line holds guess/mask pairs rather than colorbars
+func hasPair(line string) bool {
+	for _, t := range tokenize(line) {
+		if strings.IndexFunc(t.text, isPairSeparator) >= 0 {
+			return true
+		}
+	}
+
+	return false
+}
+
+// addLine adds one line of input. Anything after a # is a comment. A line of
+// guess/mask pairs holds guesses, any other line holds colorbars. A blank line
+// or the header of a shared grid ends the current player's colorbars.
+func (in *puzzleInput) addLine(line string, alpha alphabet) error {
+	line, _, _ = strings.Cut(line, "#")
+
+	if strings.TrimSpace(line) == "" || isShareHeader(line) {
+		in.open = false
+		return nil
+	}
+
+	if hasPair(line) {
+		words, masks, err := unpackGuessed(line, alpha)
+		if err != nil {
+			return err
+		}
+		in.guessWords = append(in.guessWords, words...)
+		in.guessMasks = append(in.guessMasks, masks...)
+		return nil
+	}
+
+	masks, err := unpackMasks(line)
+	if err != nil {
+		return err
+	}
+	if !in.open {
+		in.grids = append(in.grids, []string{})
+		in.open = true
+	}
+	in.grids[len(in.grids)-1] = append(in.grids[len(in.grids)-1], masks...)
+
+	return nil
+}
+
+// readInput reads colorbars and guesses, one row per line
+func readInput(r io.Reader, alpha alphabet) (puzzleInput, error) {
+	in := newPuzzleInput()
+	errs := []string{}
+
+	scanner := bufio.NewScanner(r)
+	for n := 1; scanner.Scan(); n++ {
+		if err := in.addLine(scanner.Text(), alpha); err != nil {
+			errs = append(errs, fmt.Sprintf("line %d: %v", n, err))
+		}
+	}
+	if err := scanner.Err(); err != nil {
+		return in, err
+	}
+
+	if len(errs) > 0 {
+		return in, fmt.Errorf("%s", strings.Join(errs, "\n"))
+	}
+
+	return in, nil
+}
+
+// openInput opens file for reading, or stdin if file is -
+func openInput(file string) (io.ReadCloser, error) {
+	if file == "-" {
+		return io.NopCloser(os.Stdin), nil
+	}
+
+	return os.Open(file)
+}
+
+// readInputFile reads input from file, or from stdin if file is -
+func readInputFile(file string, alpha alphabet) (puzzleInput, error) {
+	f, err := openInput(file)
+	if err != nil {
+		return puzzleInput{}, err
+	}
+	defer f.Close()
+
+	return readInput(f, alpha)
+}
@@ -0,0 +1,65 @@
+package main
+
+import (
+	"strings"
+	"testing"
+)
+
+func TestReadInput(t *testing.T) {
+	testCases := []struct {
+		s          string
+		grids      int
+		masks      []string
+		guessWords []string
+		guessMasks []string
+	}{
+		{"", 0, []string{}, []string{}, []string{}},
+		{"# just a comment\n\n", 0, []string{}, []string{}, []string{}},
+		{"bbybb\nggbbb\n", 1, []string{"bbybb", "ggbbb"}, []string{}, []string{}},
+		{"bbybb\n\n\nggbbb # second player\n", 2, []string{"bbybb", "ggbbb"}, []string{}, []string{}},
+		{"🟩🟩⬛⬛🟨\n", 1, []string{"ggbby"}, []string{}, []string{}},
+		{"Wordle 1,234 2/6\nbbybb\nggggg\nWordle 1,234 1/6\nggggg\n", 2, []string{"bbybb", "ggggg", "ggggg"}, []string{}, []string{}},
+		{"bbybb\ncrane/bbybb\nmoist / bbbbb\n", 1, []string{"bbybb"}, []string{"crane", "moist"}, []string{"bbybb", "bbbbb"}},
+	}
+
+	for _, testCase := range testCases {
+		in, err := readInput(strings.NewReader(testCase.s), alphabets["en"])
+		if err != nil {
+			t.Errorf("ERROR: For %q expected no error, got %v", testCase.s, err)
+			continue
+		}
+		if len(in.grids) != testCase.grids {
+			t.Errorf("ERROR: For %q expected %d grids, got %d", testCase.s, testCase.grids, len(in.grids))
+		}
+		if !equal(in.masks(), testCase.masks) {
+			t.Errorf("ERROR: For %q expected masks %v, got %v", testCase.s, testCase.masks, in.masks())
+		}
+		if !equal(in.guessWords, testCase.guessWords) || !equal(in.guessMasks, testCase.guessMasks) {
+			t.Errorf("ERROR: For %q expected guesses %v %v, got %v %v", testCase.s, testCase.guessWords, testCase.guessMasks, in.guessWords, in.guessMasks)
+		}
+	}
+}
+
+func TestReadInputErrors(t *testing.T) {
+	testCases := []struct {
+		s        string
+		expected []string
+	}{
+		{"bbybb\nbbqbb\n", []string{"line 2:", "unknown tile 'q'"}},
+		{"Worlde 1,234 4/6\nbbybb\n", []string{"line 1:"}},
+		{"bbzbb\n\ncrane/bbzbb\n", []string{"line 1:", "line 3:"}},
+	}
+
+	for _, testCase := range testCases {
+		_, err := readInput(strings.NewReader(testCase.s), alphabets["en"])
+		if err == nil {
+			t.Errorf("ERROR: For %q expected an error, got nil", testCase.s)
+			continue
+		}
+		for _, want := range testCase.expected {
+			if !strings.Contains(err.Error(), want) {
+				t.Errorf("ERROR: For %q expected '%s' in %v", testCase.s, want, err)
+			}
+		}
+	}
+}
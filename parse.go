@@ -0,0 +1,171 @@
+package main
+
+import (
+	"errors"
+	"fmt"
+	"strings"
+	"unicode"
+)
+
+// tileAliases maps the other ways people write tiles to g, y or b
+var tileAliases = map[rune]rune{
+	'🟩': 'g', // green
+	'🟧': 'g', // green in high contrast mode
+	'🟨': 'y', // yellow
+	'🟦': 'y', // yellow in high contrast mode
+	'⬛': 'b', // black in dark mode
+	'⬜': 'b', // black in light mode
+	'.': 'b',
+	'-': 'b',
+	'x': 'b', // gray
+}
+
+// Characters that can appear in a mask once aliases have been applied
+const maskRunes = "gyb0123456789"
+
+// token is one entry of user input and the column each of its runes came
+// from. Whitespace dropped from inside an entry leaves gaps in the columns.
+type token struct {
+	text string
+	cols []int
+}
+
+// col returns the column the token starts at
+func (t token) col() int {
+	if len(t.cols) == 0 {
+		return 0
+	}
+
+	return t.cols[0]
+}
+
+// problems collects every problem found in user input, so they can all be
+// reported at once
+type problems []string
+
+// add records a problem found at the given column
+func (p *problems) add(col int, format string, a ...any) {
+	*p = append(*p, fmt.Sprintf("column %d: %s", col, fmt.Sprintf(format, a...)))
+}
+
+// err returns all of the problems as a single error, or nil if there are none
+func (p problems) err() error {
+	if len(p) == 0 {
+		return nil
+	}
+
+	return errors.New(strings.Join(p, "; "))
+}
+
+// isSeparator returns true if r separates one entry from the next
+func isSeparator(r rune) bool {
+	return r == ',' || r == ';' || unicode.IsSpace(r)
+}
+
+// isPairSeparator returns true if r separates a guess from its mask
+func isPairSeparator(r rune) bool {
+	return r == '/' || r == ':' || r == '='
+}
+
+// tokenize splits s into entries. Whitespace next to a pair separator does not
+// end an entry, so "crane / gybbb" is a single entry.
+func tokenize(s string) []token {
+	runes := []rune(s)
+	tokens := []token{}
+	entry := []rune{}
+	cols := []int{}
+
+	for i := 0; i < len(runes); i++ {
+		r := runes[i]
+
+		if unicode.IsSpace(r) && len(entry) > 0 {
+			if isPairSeparator(entry[len(entry)-1]) {
+				continue
+			}
+			next := i
+			for next < len(runes) && unicode.IsSpace(runes[next]) {
+				next++
+			}
+			if next < len(runes) && isPairSeparator(runes[next]) {
+				i = next - 1
+				continue
+			}
+		}
+
+		if isSeparator(r) {
+			if len(entry) > 0 {
+				tokens = append(tokens, token{string(entry), cols})
+				entry = []rune{}
+				cols = []int{}
+			}
+			continue
+		}
+
+		entry = append(entry, r)
+		cols = append(cols, i+1)
+	}
+
+	if len(entry) > 0 {
+		tokens = append(tokens, token{string(entry), cols})
+	}
+
+	return tokens
+}
+
+// canonicalMask returns the mask in t lowercased and with aliases replaced. It
+// records a problem for each character that cannot be part of a mask.
+func canonicalMask(t token, p *problems) (string, bool) {
+	mask := []rune{}
+	ok := true
+
+	for i, r := range []rune(t.text) {
+		// Emoji are sometimes followed by a variation selector
+		if r == '\uFE0F' {
+			continue
+		}
+		r = unicode.ToLower(r)
+		if alias, found := tileAliases[r]; found {
+			r = alias
+		}
+		if !strings.ContainsRune(maskRunes, r) {
+			p.add(t.cols[i], "unknown tile %q in %s", r, t.text)
+			ok = false
+			continue
+		}
+		mask = append(mask, r)
+	}
+
+	return string(mask), ok
+}
+
+// splitPair returns the guess and mask tokens of a guess/mask entry
+func splitPair(t token) (token, token, bool) {
+	runes := []rune(t.text)
+	sep := -1
+
+	for i, r := range runes {
+		if !isPairSeparator(r) {
+			continue
+		}
+		if sep >= 0 {
+			return token{}, token{}, false
+		}
+		sep = i
+	}
+	if sep < 0 {
+		return token{}, token{}, false
+	}
+
+	// An empty side still has a place in the input to report problems at
+	wordCols, maskCols := t.cols[:sep], t.cols[sep+1:]
+	if len(wordCols) == 0 {
+		wordCols = []int{t.cols[sep]}
+	}
+	if len(maskCols) == 0 {
+		maskCols = []int{t.cols[sep] + 1}
+	}
+	word := token{string(runes[:sep]), wordCols}
+	mask := token{string(runes[sep+1:]), maskCols}
+
+	return word, mask, true
+}
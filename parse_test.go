@@ -0,0 +1,144 @@
+package main
+
+import (
+	"reflect"
+	"strings"
+	"testing"
+	"unicode/utf8"
+)
+
+// tok returns a token whose runes come from consecutive columns starting at col
+func tok(text string, col int) token {
+	cols := []int{}
+	for i := 0; i < utf8.RuneCountInString(text); i++ {
+		cols = append(cols, col+i)
+	}
+
+	return token{text, cols}
+}
+
+func equalTokens(a, b []token) bool {
+	return reflect.DeepEqual(a, b)
+}
+
+func TestTokenize(t *testing.T) {
+	testCases := []struct {
+		s        string
+		expected []token
+	}{
+		{"", []token{}},
+		{" ,; ", []token{}},
+		{"abc", []token{tok("abc", 1)}},
+		{"abc,def", []token{tok("abc", 1), tok("def", 5)}},
+		{"abc, def", []token{tok("abc", 1), tok("def", 6)}},
+		{"abc;def\nghi\tjkl", []token{tok("abc", 1), tok("def", 5), tok("ghi", 9), tok("jkl", 13)}},
+		{"abc,,def,", []token{tok("abc", 1), tok("def", 6)}},
+		{"crane / gybbb", []token{{"crane/gybbb", []int{1, 2, 3, 4, 5, 7, 9, 10, 11, 12, 13}}}},
+		{"crane  /  gyqbb", []token{{"crane/gyqbb", []int{1, 2, 3, 4, 5, 8, 11, 12, 13, 14, 15}}}},
+		{"crane /gybbb, moist: bbbby", []token{{"crane/gybbb", []int{1, 2, 3, 4, 5, 7, 8, 9, 10, 11, 12}}, {"moist:bbbby", []int{15, 16, 17, 18, 19, 20, 22, 23, 24, 25, 26}}}},
+		{"🟩⬛ 🟨⬛", []token{tok("🟩⬛", 1), tok("🟨⬛", 4)}},
+	}
+
+	for _, testCase := range testCases {
+		answer := tokenize(testCase.s)
+		if !equalTokens(answer, testCase.expected) {
+			t.Errorf("ERROR: For '%s' expected %v, got %v", testCase.s, testCase.expected, answer)
+		}
+	}
+}
+
+func TestCanonicalMask(t *testing.T) {
+	testCases := []struct {
+		t        token
+		expected string
+		ok       bool
+		problems []string
+	}{
+		{tok("", 1), "", true, []string{}},
+		{tok("gyb", 1), "gyb", true, []string{}},
+		{tok("GYB", 1), "gyb", true, []string{}},
+		{tok(".-xX", 1), "bbbb", true, []string{}},
+		{tok("🟩🟨⬛⬜", 1), "gybb", true, []string{}},
+		{tok("🟧🟦", 1), "gy", true, []string{}},
+		{tok("2g1y", 1), "2g1y", true, []string{}},
+		{tok("gzbq", 5), "gb", false, []string{"column 6: unknown tile 'z' in gzbq", "column 8: unknown tile 'q' in gzbq"}},
+	}
+
+	for _, testCase := range testCases {
+		p := problems{}
+		answer, ok := canonicalMask(testCase.t, &p)
+		if answer != testCase.expected || ok != testCase.ok {
+			t.Errorf("ERROR: For %v expected %s %t, got %s %t", testCase.t, testCase.expected, testCase.ok, answer, ok)
+		}
+		if !equal(p, testCase.problems) {
+			t.Errorf("ERROR: For %v expected %v, got %v", testCase.t, testCase.problems, p)
+		}
+	}
+}
+
+func TestSplitPair(t *testing.T) {
+	testCases := []struct {
+		t     token
+		word  token
+		mask  token
+		valid bool
+	}{
+		{tok("crane/gybbb", 1), tok("crane", 1), tok("gybbb", 7), true},
+		{tok("crane:gybbb", 3), tok("crane", 3), tok("gybbb", 9), true},
+		{tok("niño=gybb", 1), tok("niño", 1), tok("gybb", 6), true},
+		{token{"crane/gybbb", []int{1, 2, 3, 4, 5, 7, 9, 10, 11, 12, 13}}, tok("crane", 1), tok("gybbb", 9), true},
+		{tok("crane/", 1), tok("crane", 1), token{"", []int{7}}, true},
+		{tok("/gybbb", 1), token{"", []int{1}}, tok("gybbb", 2), true},
+		{tok("crane", 1), token{}, token{}, false},
+		{tok("crane/gy/bbb", 1), token{}, token{}, false},
+	}
+
+	for _, testCase := range testCases {
+		word, mask, ok := splitPair(testCase.t)
+		if !reflect.DeepEqual(word, testCase.word) || !reflect.DeepEqual(mask, testCase.mask) || ok != testCase.valid {
+			t.Errorf("ERROR: For %v expected %v %v %t, got %v %v %t", testCase.t, testCase.word, testCase.mask, testCase.valid, word, mask, ok)
+		}
+	}
+}
+
+func TestProblems(t *testing.T) {
+	p := problems{}
+	if p.err() != nil {
+		t.Errorf("ERROR: expected no error, got %v", p.err())
+	}
+
+	p.add(3, "bad %s", "thing")
+	p.add(7, "worse")
+	expected := "column 3: bad thing; column 7: worse"
+	if p.err() == nil || p.err().Error() != expected {
+		t.Errorf("ERROR: expected %s, got %v", expected, p.err())
+	}
+}
+
+func TestUnpackReportsAllProblems(t *testing.T) {
+	_, _, err := unpackGuessed("crane/gyzbb,moist/ggg,foo", alphabets["en"])
+	if err == nil {
+		t.Fatalf("ERROR: expected an error, got nil")
+	}
+	for _, col := range []string{"column 9:", "column 19:", "column 23:"} {
+		if !strings.Contains(err.Error(), col) {
+			t.Errorf("ERROR: expected %s in %v", col, err)
+		}
+	}
+
+	// Columns count the whitespace dropped from inside a pair
+	_, _, err = unpackGuessed("crane  /  gyqbb", alphabets["en"])
+	if err == nil || !strings.Contains(err.Error(), "column 13:") {
+		t.Errorf("ERROR: expected column 13 in %v", err)
+	}
+
+	_, err = unpackMasks("gybbb,gyb,bqbbz")
+	if err == nil {
+		t.Fatalf("ERROR: expected an error, got nil")
+	}
+	for _, col := range []string{"column 7:", "column 12:", "column 15:"} {
+		if !strings.Contains(err.Error(), col) {
+			t.Errorf("ERROR: expected %s in %v", col, err)
+		}
+	}
+}
@@ -0,0 +1,267 @@
+package main
+
+import (
+	"fmt"
+	"math/rand"
+	"strconv"
+	"strings"
+	"unicode/utf8"
+
+	"github.com/erikbryant/dictionaries"
+)
+
+// How often a player guesses a word they would not normally use. Inference
+// allows for it so that no colorbar is ever impossible.
+const rareGuess = 0.05
+
+// playerModel describes how a simulated person plays
+type playerModel struct {
+	strategy string  // how a candidate is picked: random or one of the strategies
+	opener   string  // the word always guessed first, if any
+	noise    float64 // chance of picking a random candidate instead of following the strategy
+	offList  float64 // chance of guessing a word that cannot be the answer
+	vocab    int     // how many of the most common words the player knows, 0 for every word
+	hard     bool    // whether every guess must use the hints revealed so far
+}
+
+// guessOdds are how likely a player is to guess each guessable word on their
+// opening turn and on later turns
+type guessOdds struct {
+	opening []float64
+	later   []float64
+}
+
+// playerPresets maps names to commonly used player models
+var playerPresets = map[string]playerModel{
+	"random": {strategy: "random"},
+	"noisy":  {strategy: "letterFreq", noise: 0.25},
+	"casual": {strategy: "random", offList: 0.2, vocab: 3000},
+	"expert": {strategy: "letterFreq", hard: true},
+}
+
+// parsePlayer returns the player model described by spec, a comma-separated
+// list of a preset or strategy name followed by any options to change:
+// strategy=name, opener=word, noise=p, offList=p, vocab=n, and hard. For
+// example "casual,opener=crane,hard".
+func parsePlayer(spec string, alpha alphabet) (playerModel, error) {
+	m := playerPresets["random"]
+
+	for i, item := range strings.Split(spec, ",") {
+		item = strings.TrimSpace(item)
+		key, value, hasValue := strings.Cut(item, "=")
+
+		if i == 0 && !hasValue && item != "hard" {
+			if preset, ok := playerPresets[item]; ok {
+				m = preset
+				continue
+			}
+			if _, ok := strategies[item]; ok {
+				m = playerModel{strategy: item}
+				continue
+			}
+			return m, fmt.Errorf("unknown player %s", item)
+		}
+
+		var err error
+		switch key {
+		case "hard":
+			m.hard = !hasValue || value == "true"
+		case "strategy":
+			m.strategy = value
+		case "opener":
+			m.opener = alpha.normalize(value)
+		case "noise":
+			m.noise, err = parseChance(value)
+		case "offList":
+			m.offList, err = parseChance(value)
+		case "vocab":
+			m.vocab, err = strconv.Atoi(value)
+			if err == nil && m.vocab < 0 {
+				err = fmt.Errorf("vocab must not be negative")
+			}
+		default:
+			err = fmt.Errorf("unknown option")
+		}
+		if err != nil {
+			return m, fmt.Errorf("player option %s: %v", item, err)
+		}
+	}
+
+	if _, ok := strategies[m.strategy]; !ok && m.strategy != "random" {
+		return m, fmt.Errorf("unknown player strategy %s", m.strategy)
+	}
+
+	return m, nil
+}
+
+// parseChance returns the probability in s
+func parseChance(s string) (float64, error) {
+	p, err := strconv.ParseFloat(s, 64)
+	if err != nil {
+		return 0, err
+	}
+	if p < 0 || p > 1 {
+		return 0, fmt.Errorf("%s is not between 0 and 1", s)
+	}
+
+	return p, nil
+}
+
+// validOpener returns an error if the player has an opener that is not one of
+// the guessable words of the given length
+func (m playerModel) validOpener(wordLen int, guessables []string) error {
+	if m.opener == "" {
+		return nil
+	}
+	if utf8.RuneCountInString(m.opener) != wordLen {
+		return fmt.Errorf("opener %s does not have %d letters", m.opener, wordLen)
+	}
+	if !dictionaries.ContainsWord(guessables, m.opener) {
+		return fmt.Errorf("opener %s is not in the dictionary", m.opener)
+	}
+
+	return nil
+}
+
+// vocabulary returns the words the player knows
+func (m playerModel) vocabulary(guessables []string, wordLen int, alpha alphabet) ([]string, error) {
+	if m.vocab == 0 {
+		return guessables, nil
+	}
+
+	return alpha.commonWords(wordLen, m.vocab)
+}
+
+// randomGuess picks any of the words that have not been guessed
+func randomGuess(words []string, h history, rng *rand.Rand) string {
+	for _, i := range rng.Perm(len(words)) {
+		if !h.guessed(words[i]) {
+			return words[i]
+		}
+	}
+
+	return ""
+}
+
+// hardModeOK returns true if guess uses every hint revealed so far: green
+// letters stay in place and yellow letters are used again. Masks that are not
+// colorbars reveal no hints.
+func hardModeOK(guess string, h history) bool {
+	g := []rune(guess)
+
+	for _, t := range h {
+		word := []rune(t.word)
+		mask := []rune(t.mask)
+		if len(word) != len(g) || len(mask) != len(g) {
+			continue
+		}
+
+		needed := map[rune]int{}
+		for i, tile := range mask {
+			switch tile {
+			case 'g':
+				if g[i] != word[i] {
+					return false
+				}
+				needed[word[i]]++
+			case 'y':
+				needed[word[i]]++
+			}
+		}
+		for r, n := range needed {
+			if strings.Count(guess, string(r)) < n {
+				return false
+			}
+		}
+	}
+
+	return true
+}
+
+// player returns a player that follows the model, guessing from the words it
+// knows
+func (m playerModel) player(known []string) player {
+	knows := map[string]bool{}
+	for _, word := range known {
+		knows[word] = true
+	}
+	suggest := strategies[m.strategy]
+
+	return func(words []string, h history, rng *rand.Rand) string {
+		if len(h) == 0 && m.opener != "" {
+			return m.opener
+		}
+
+		if rng.Float64() < m.offList {
+			candidate := map[string]bool{}
+			for _, word := range words {
+				candidate[word] = true
+			}
+			for _, i := range rng.Perm(len(known)) {
+				guess := known[i]
+				if !candidate[guess] && !h.guessed(guess) && (!m.hard || hardModeOK(guess, h)) {
+					return guess
+				}
+			}
+		}
+
+		// Prefer the candidates the player knows, if they know any
+		pool := []string{}
+		for _, word := range words {
+			if knows[word] {
+				pool = append(pool, word)
+			}
+		}
+		if len(pool) == 0 {
+			pool = words
+		}
+
+		if suggest == nil || rng.Float64() < m.noise {
+			return randomGuess(pool, h, rng)
+		}
+		return suggest(pool, h)
+	}
+}
+
+// odds returns how likely the model is to guess each of the guessable words.
+// The candidates on a later turn depend on guesses that were not shared, so a
+// later guess is taken to be any known word, or rarely any other word.
+func (m playerModel) odds(guessables, known []string) guessOdds {
+	knows := map[string]bool{}
+	for _, word := range known {
+		knows[word] = true
+	}
+	inList := 0
+	for _, word := range guessables {
+		if knows[word] {
+			inList++
+		}
+	}
+	outList := len(guessables) - inList
+
+	later := make([]float64, len(guessables))
+	for i, word := range guessables {
+		switch {
+		case inList == 0 || outList == 0:
+			later[i] = 1 / float64(len(guessables))
+		case knows[word]:
+			later[i] = (1 - rareGuess) / float64(inList)
+		default:
+			later[i] = rareGuess / float64(outList)
+		}
+	}
+
+	if m.opener == "" {
+		return guessOdds{later, later}
+	}
+
+	opening := make([]float64, len(guessables))
+	for i, word := range guessables {
+		opening[i] = rareGuess * later[i]
+		if word == m.opener {
+			opening[i] += 1 - rareGuess
+		}
+	}
+
+	return guessOdds{opening, later}
+}
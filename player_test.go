@@ -0,0 +1,129 @@
+package main
+
+import (
+	"math"
+	"math/rand"
+	"testing"
+)
+
+func TestParsePlayer(t *testing.T) {
+	testCases := []struct {
+		spec     string
+		expected playerModel
+	}{
+		{"random", playerModel{strategy: "random"}},
+		{"noisy", playerModel{strategy: "letterFreq", noise: 0.25}},
+		{"letterFreq", playerModel{strategy: "letterFreq"}},
+		{"casual,opener=Crane", playerModel{strategy: "random", opener: "crane", offList: 0.2, vocab: 3000}},
+		{"expert, hard=false, noise=0.5", playerModel{strategy: "letterFreq", noise: 0.5}},
+		{"hard", playerModel{strategy: "random", hard: true}},
+		{"random,strategy=letterFreq,offList=1,vocab=10", playerModel{strategy: "letterFreq", offList: 1, vocab: 10}},
+	}
+
+	for _, testCase := range testCases {
+		answer, err := parsePlayer(testCase.spec, alphabets["en"])
+		if err != nil || answer != testCase.expected {
+			t.Errorf("ERROR: For %s expected %+v, got %+v %v", testCase.spec, testCase.expected, answer, err)
+		}
+	}
+}
+
+func TestParsePlayerErrors(t *testing.T) {
+	testCases := []string{
+		"",
+		"nobody",
+		"random,noise=2",
+		"random,offList=x",
+		"random,vocab=-1",
+		"random,colour=red",
+		"random,strategy=nobody",
+	}
+
+	for _, testCase := range testCases {
+		_, err := parsePlayer(testCase, alphabets["en"])
+		if err == nil {
+			t.Errorf("ERROR: For %q expected an error, got nil", testCase)
+		}
+	}
+}
+
+func TestHardModeOK(t *testing.T) {
+	testCases := []struct {
+		guess    string
+		h        history
+		expected bool
+	}{
+		{"dog", history{}, true},
+		{"cot", history{{"cat", "gbg"}}, true},
+		{"dog", history{{"cat", "gbg"}}, false},
+		{"act", history{{"cat", "yyg"}}, true},
+		{"tag", history{{"cat", "ybb"}}, false},
+		{"act", history{{"cat", "ybb"}}, true},
+		{"dog", history{{"cat", "2"}}, true},
+	}
+
+	for _, testCase := range testCases {
+		answer := hardModeOK(testCase.guess, testCase.h)
+		if answer != testCase.expected {
+			t.Errorf("ERROR: For %s %v expected %t, got %t", testCase.guess, testCase.h, testCase.expected, answer)
+		}
+	}
+}
+
+func TestOdds(t *testing.T) {
+	sum := func(odds []float64) float64 {
+		total := 0.0
+		for _, o := range odds {
+			total += o
+		}
+		return total
+	}
+
+	testCases := []struct {
+		m     playerModel
+		known []string
+	}{
+		{playerModel{}, consistencyTestWords},
+		{playerModel{}, []string{"cat", "dog"}},
+		{playerModel{}, []string{"zzz"}},
+		{playerModel{opener: "dog"}, []string{"cat", "dog"}},
+	}
+
+	for _, testCase := range testCases {
+		odds := testCase.m.odds(consistencyTestWords, testCase.known)
+		if math.Abs(sum(odds.opening)-1) > 1e-9 || math.Abs(sum(odds.later)-1) > 1e-9 {
+			t.Errorf("ERROR: For %+v %v expected odds summing to 1, got %v", testCase.m, testCase.known, odds)
+		}
+	}
+
+	odds := playerModel{opener: "dog"}.odds(consistencyTestWords, []string{"cat", "dog"})
+	if odds.opening[2] < 1-rareGuess || odds.later[0] != odds.later[2] || odds.later[0] <= odds.later[1] {
+		t.Errorf("ERROR: expected dog to open and known words to be likelier, got %v", odds)
+	}
+}
+
+func TestPlayer(t *testing.T) {
+	rng := rand.New(rand.NewSource(1))
+
+	p := playerModel{strategy: "random", opener: "dog"}.player(consistencyTestWords)
+	if guess := p(consistencyTestWords, history{}, rng); guess != "dog" {
+		t.Errorf("ERROR: expected the opener dog, got %s", guess)
+	}
+
+	// A player only guesses candidates they know, when there are any
+	p = playerModel{strategy: "random"}.player([]string{"act"})
+	for i := 0; i < 10; i++ {
+		if guess := p([]string{"cat", "act"}, history{{"dog", "bbb"}}, rng); guess != "act" {
+			t.Errorf("ERROR: expected the known candidate act, got %s", guess)
+		}
+	}
+
+	// An off-list guess is never a candidate, and in hard mode uses the hints
+	p = playerModel{strategy: "random", offList: 1, hard: true}.player(consistencyTestWords)
+	h := history{{"act", "yyb"}}
+	for i := 0; i < 10; i++ {
+		if guess := p([]string{"tag"}, h, rng); guess != "cat" {
+			t.Errorf("ERROR: expected the off-list guess cat, got %s", guess)
+		}
+	}
+}
@@ -0,0 +1,254 @@
+package main
+
+import (
+	"encoding/csv"
+	"fmt"
+	"io"
+	"math"
+	"os"
+	"strconv"
+
+	"github.com/erikbryant/dictionaries"
+)
+
+// dayReport is how much the colorbars helped on one day with a known answer
+type dayReport struct {
+	id         string
+	candidates int  // words left by the colorbars
+	found      bool // whether the answer was one of them
+	rank       int  // rank of the answer by likelihood, 0 if not found
+	without    int  // guesses to solve from every mystery word, 0 if unsolved
+	with       int  // guesses to solve from the candidates, 0 if unsolved
+}
+
+// saved returns how many guesses the colorbars saved, if both games were solved
+func (d dayReport) saved() (int, bool) {
+	if d.without == 0 || d.with == 0 {
+		return 0, false
+	}
+
+	return d.without - d.with, true
+}
+
+// likelihood returns the log of the probability of seeing the players' grids
+// if word is the answer, given how likely each guess is. The first colorbar of
+// a grid is the player's opening guess.
+func likelihood(word string, guessables []string, grids [][]string, odds guessOdds) float64 {
+	opening := map[string]float64{}
+	later := map[string]float64{}
+	for i, guess := range guessables {
+		mask := makeMask(word, guess)
+		opening[mask] += odds.opening[i]
+		later[mask] += odds.later[i]
+	}
+
+	l := 0.0
+	for _, grid := range grids {
+		for i, mask := range grid {
+			if i == 0 {
+				l += math.Log(opening[mask])
+				continue
+			}
+			l += math.Log(later[mask])
+		}
+	}
+
+	return l
+}
+
+// rankAnswer returns the position of the answer when the candidates are
+// sorted most likely first, with ties counted in its favor, or 0 if the
+// answer is not a candidate
+func rankAnswer(answer string, candidates, guessables []string, grids [][]string, odds guessOdds) int {
+	if !dictionaries.ContainsWord(candidates, answer) {
+		return 0
+	}
+
+	target := likelihood(answer, guessables, grids, odds)
+	rank := 1
+	for _, c := range candidates {
+		if c != answer && likelihood(c, guessables, grids, odds) > target {
+			rank++
+		}
+	}
+
+	return rank
+}
+
+// reportDay returns how much the colorbars helped on the day
+func reportDay(r dayResult, words wordLists, odds guessOdds, strategy string, book *openingBook) dayReport {
+	suggest := strategies[strategy]
+	answer := r.day.answer
+	d := dayReport{id: r.day.id, candidates: len(r.candidates)}
+
+	if !book.covers(words.mysteries, strategy) {
+		book = nil
+	}
+	if g := playGame(answer, words.mysteries, suggest, book); g.found {
+		d.without = len(g.turns)
+	}
+
+	d.rank = rankAnswer(answer, r.candidates, words.guessables, r.day.input.grids, odds)
+	d.found = d.rank > 0
+	if !d.found {
+		return d
+	}
+	if g := playGame(answer, r.candidates, suggest, nil); g.found {
+		d.with = len(g.turns)
+	}
+
+	return d
+}
+
+// writeReportCSV writes one row per day to w
+func writeReportCSV(w io.Writer, reports []dayReport) error {
+	c := csv.NewWriter(w)
+
+	c.Write([]string{"day", "candidates", "found", "rank", "guesses_without", "guesses_with", "saved"})
+	for _, d := range reports {
+		saved := ""
+		if s, ok := d.saved(); ok {
+			saved = strconv.Itoa(s)
+		}
+		c.Write([]string{
+			d.id,
+			strconv.Itoa(d.candidates),
+			strconv.FormatBool(d.found),
+			strconv.Itoa(d.rank),
+			strconv.Itoa(d.without),
+			strconv.Itoa(d.with),
+			saved,
+		})
+	}
+	c.Flush()
+
+	return c.Error()
+}
+
+// printReportSummary prints the report aggregated over every day
+func printReportSummary(reports []dayReport) {
+	if len(reports) == 0 {
+		return
+	}
+
+	found, first, top10, candidates, ranks := 0, 0, 0, 0, 0
+	solved, without, with := 0, 0, 0
+	for _, d := range reports {
+		candidates += d.candidates
+		if !d.found {
+			continue
+		}
+		found++
+		ranks += d.rank
+		if d.rank == 1 {
+			first++
+		}
+		if d.rank <= 10 {
+			top10++
+		}
+		if _, ok := d.saved(); ok {
+			solved++
+			without += d.without
+			with += d.with
+		}
+	}
+
+	days := len(reports)
+	fmt.Println()
+	fmt.Printf("Days with a known answer: %d\n", days)
+	fmt.Printf("Answer among candidates:  %d (%.1f%%)\n", found, percent(found, days))
+	fmt.Printf("Mean candidates:          %.1f\n", float64(candidates)/float64(days))
+	if found > 0 {
+		fmt.Printf("Mean rank of the answer:  %.1f\n", float64(ranks)/float64(found))
+		fmt.Printf("Answer ranked first:      %d (%.1f%%)\n", first, percent(first, found))
+		fmt.Printf("Answer in the top 10:     %d (%.1f%%)\n", top10, percent(top10, found))
+	}
+	if solved > 0 {
+		fmt.Printf("Mean guesses without colorbars: %.2f\n", float64(without)/float64(solved))
+		fmt.Printf("Mean guesses with colorbars:    %.2f\n", float64(with)/float64(solved))
+		fmt.Printf("Mean guesses saved:             %.2f\n", float64(without-with)/float64(solved))
+	}
+}
+
+// runReport measures how much the colorbars help on each puzzle day in file
+// whose answer is known, from the file or the schedule, and prints a report.
+// Answers are ranked assuming the grids were shared by players following the
+// player model in spec. If csvFile is set the report is also written there.
+func runReport(file, scheduleFile, csvFile string, alpha alphabet, workers int, strategy, spec string, book *openingBook) error {
+	m, err := parsePlayer(spec, alpha)
+	if err != nil {
+		return err
+	}
+
+	var s schedule
+	if scheduleFile != "" {
+		s, err = loadSchedule(scheduleFile, alpha)
+		if err != nil {
+			return err
+		}
+	}
+
+	days, lists, err := loadBatch(file, alpha, s)
+	if err != nil {
+		return err
+	}
+
+	known := []puzzleDay{}
+	for _, day := range days {
+		if day.answer != "" {
+			known = append(known, day)
+		}
+	}
+	if len(known) == 0 {
+		return fmt.Errorf("no puzzle day in %s has a known answer", file)
+	}
+
+	odds := map[int]guessOdds{}
+	for length, words := range lists {
+		err = m.validOpener(length, words.guessables)
+		if err != nil {
+			return err
+		}
+		vocab, err := m.vocabulary(words.guessables, length, alpha)
+		if err != nil {
+			return err
+		}
+		odds[length] = m.odds(words.guessables, vocab)
+	}
+
+	results := crackDays(known, lists, workers)
+	reports := make([]dayReport, len(results))
+	parallel(len(results), workers, func(i int) {
+		length, _ := dayLength(results[i].day)
+		reports[i] = reportDay(results[i], lists[length], odds[length], strategy, book)
+	})
+
+	for _, d := range reports {
+		if !d.found {
+			fmt.Printf("%s: answer MISSED, %d candidates\n", d.id, d.candidates)
+			continue
+		}
+		fmt.Printf("%s: %d candidates, answer ranked %d", d.id, d.candidates, d.rank)
+		if saved, ok := d.saved(); ok {
+			fmt.Printf(", solved in %d guesses instead of %d (saved %d)", d.with, d.without, saved)
+		}
+		fmt.Println()
+	}
+	printReportSummary(reports)
+
+	if csvFile == "" {
+		return nil
+	}
+
+	f, err := os.Create(csvFile)
+	if err != nil {
+		return err
+	}
+	err = writeReportCSV(f, reports)
+	if err != nil {
+		f.Close()
+		return err
+	}
+
+	return f.Close()
+}
@@ -0,0 +1,96 @@
+package main
+
+import (
+	"bytes"
+	"math"
+	"testing"
+)
+
+func TestLikelihood(t *testing.T) {
+	uniform := playerModel{}.odds(consistencyTestWords, consistencyTestWords)
+
+	testCases := []struct {
+		word     string
+		masks    []string
+		expected float64
+	}{
+		{"cat", []string{}, 0},
+		{"cat", []string{"ggg"}, math.Log(1.0 / 5)},
+		{"cat", []string{"gbg"}, math.Log(1.0 / 5)},
+		{"cat", []string{"bbb"}, math.Log(1.0 / 5)},
+		{"cat", []string{"bbb", "bbb"}, 2 * math.Log(1.0/5)},
+		{"cat", []string{"yyg"}, math.Log(1.0 / 5)},
+		{"dog", []string{"bbb"}, math.Log(2.0 / 5)},
+		{"cat", []string{"yyy"}, math.Inf(-1)},
+		{"cot", []string{"bbb"}, math.Inf(-1)},
+	}
+
+	for _, testCase := range testCases {
+		answer := likelihood(testCase.word, consistencyTestWords, [][]string{testCase.masks}, uniform)
+		if math.Abs(answer-testCase.expected) > 1e-9 && answer != testCase.expected {
+			t.Errorf("ERROR: For %s %v expected %f, got %f", testCase.word, testCase.masks, testCase.expected, answer)
+		}
+	}
+}
+
+func TestRankAnswer(t *testing.T) {
+	uniform := playerModel{}.odds(consistencyTestWords, consistencyTestWords)
+
+	testCases := []struct {
+		answer     string
+		candidates []string
+		masks      []string
+		expected   int
+	}{
+		{"cat", []string{"cat", "dog"}, []string{"bbb"}, 2},
+		{"dog", []string{"cat", "dog"}, []string{"bbb"}, 1},
+		{"cat", []string{"cat", "act"}, []string{}, 1},
+		{"act", []string{"cat", "act"}, []string{}, 1},
+		{"dog", []string{"cat", "cot"}, []string{"bbb"}, 0},
+	}
+
+	for _, testCase := range testCases {
+		answer := rankAnswer(testCase.answer, testCase.candidates, consistencyTestWords, [][]string{testCase.masks}, uniform)
+		if answer != testCase.expected {
+			t.Errorf("ERROR: For %s %v expected %d, got %d", testCase.answer, testCase.candidates, testCase.expected, answer)
+		}
+	}
+}
+
+func TestReportDay(t *testing.T) {
+	words := wordLists{consistencyTestWords, consistencyTestWords}
+	uniform := playerModel{}.odds(consistencyTestWords, consistencyTestWords)
+	day := puzzleDay{"1", "dog", puzzleInput{grids: [][]string{{"bbb"}}}}
+
+	d := reportDay(dayResult{day, []string{"cat", "dog"}, nil}, words, uniform, "letterFreq", nil)
+	if d.id != "1" || d.candidates != 2 || !d.found || d.rank != 1 || d.without == 0 || d.with == 0 {
+		t.Errorf("ERROR: expected dog found and solved both ways, got %+v", d)
+	}
+	if saved, ok := d.saved(); !ok || saved != d.without-d.with {
+		t.Errorf("ERROR: expected saved %d, got %d %t", d.without-d.with, saved, ok)
+	}
+
+	d = reportDay(dayResult{day, []string{"cat"}, nil}, words, uniform, "letterFreq", nil)
+	if d.found || d.rank != 0 || d.with != 0 {
+		t.Errorf("ERROR: expected dog missed, got %+v", d)
+	}
+	if _, ok := d.saved(); ok {
+		t.Errorf("ERROR: expected nothing saved when the answer is missed")
+	}
+}
+
+func TestWriteReportCSV(t *testing.T) {
+	reports := []dayReport{
+		{"1", 12, true, 3, 5, 3},
+		{"2024-05-01", 0, false, 0, 4, 0},
+	}
+	expected := "day,candidates,found,rank,guesses_without,guesses_with,saved\n" +
+		"1,12,true,3,5,3,2\n" +
+		"2024-05-01,0,false,0,4,0,\n"
+
+	var b bytes.Buffer
+	err := writeReportCSV(&b, reports)
+	if err != nil || b.String() != expected {
+		t.Errorf("ERROR: expected %q, got %q %v", expected, b.String(), err)
+	}
+}
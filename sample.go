@@ -0,0 +1,214 @@
+package main
+
+import (
+	"fmt"
+	"math"
+	"math/rand"
+	"sort"
+	"strings"
+)
+
+// The vowels used to find a word's letter pattern
+const vowels = "aeiouyáéíóúü"
+
+// sampling describes which of the mystery words a benchmark plays
+type sampling struct {
+	size int    // how many words to play, 0 for every word
+	by   string // how words are grouped first: "" for not at all, first (letter), or pattern
+	seed int64
+}
+
+// stratum returns the group a word is sampled from
+func stratum(word, by string) string {
+	switch by {
+	case "first":
+		for _, r := range word {
+			return string(r)
+		}
+	case "pattern":
+		pattern := []rune{}
+		for _, r := range word {
+			if strings.ContainsRune(vowels, r) {
+				pattern = append(pattern, 'v')
+			} else {
+				pattern = append(pattern, 'c')
+			}
+		}
+		return string(pattern)
+	}
+
+	return ""
+}
+
+// valid returns an error if the sampling cannot be done
+func (s sampling) valid() error {
+	if s.size < 0 || s.size == 1 {
+		return fmt.Errorf("sample size %d must be 0 for every word, or at least 2", s.size)
+	}
+	if s.by != "" && s.by != "first" && s.by != "pattern" {
+		return fmt.Errorf("unknown stratification %s", s.by)
+	}
+
+	return nil
+}
+
+// sampled returns true if only some of the words are played
+func (s sampling) sampled(words []string) bool {
+	return s.size > 0 && s.size < len(words)
+}
+
+// strata groups the words by stratum
+func strata(words []string, by string) map[string][]string {
+	groups := map[string][]string{}
+
+	for _, word := range words {
+		key := stratum(word, by)
+		groups[key] = append(groups[key], word)
+	}
+
+	return groups
+}
+
+// pick returns the words to play, in dictionary order. If the sample is big
+// enough every group gets two words, so that its spread can be measured, and
+// the rest of the sample is shared out in proportion to what each group has
+// left. The same seed picks the same words.
+func (s sampling) pick(words []string) []string {
+	if !s.sampled(words) {
+		return words
+	}
+
+	rng := rand.New(rand.NewSource(s.seed))
+	groups := strata(words, s.by)
+	keys := []string{}
+	for key := range groups {
+		keys = append(keys, key)
+	}
+	sort.Strings(keys)
+
+	shares := map[string]int{}
+	needed := 0
+	for _, key := range keys {
+		needed += min(2, len(groups[key]))
+	}
+	if s.size >= needed {
+		for _, key := range keys {
+			shares[key] = min(2, len(groups[key]))
+		}
+	}
+
+	// Give each group its whole share of what is left, then hand out the
+	// rest to the groups with the largest remainders
+	given, spare := 0, 0
+	for _, key := range keys {
+		given += shares[key]
+		spare += len(groups[key]) - shares[key]
+	}
+	left := s.size - given
+	remainders := map[string]float64{}
+	for _, key := range keys {
+		quota := float64(left) * float64(len(groups[key])-shares[key]) / float64(spare)
+		shares[key] += int(quota)
+		remainders[key] = quota - math.Floor(quota)
+		given += int(quota)
+	}
+	byRemainder := append([]string{}, keys...)
+	sort.SliceStable(byRemainder, func(i, j int) bool {
+		return remainders[byRemainder[i]] > remainders[byRemainder[j]]
+	})
+	for i := 0; given < s.size; i++ {
+		shares[byRemainder[i]]++
+		given++
+	}
+
+	picked := []string{}
+	for _, key := range keys {
+		group := groups[key]
+		for _, i := range rng.Perm(len(group))[:shares[key]] {
+			picked = append(picked, group[i])
+		}
+	}
+	sort.Strings(picked)
+
+	return picked
+}
+
+// unsampled returns how many groups of the population have no word among
+// the sampled words
+func (s sampling) unsampled(population, words []string) int {
+	played := strata(words, s.by)
+	missed := 0
+
+	for key := range strata(population, s.by) {
+		if _, ok := played[key]; !ok {
+			missed++
+		}
+	}
+
+	return missed
+}
+
+// estimate returns the estimated mean guesses over every word of population,
+// from the guesses played on the sampled words, and the half-width of its 95%
+// confidence interval. A group with one sampled word is taken to vary as much
+// as the sampled groups do on average. A group with none is taken to have the
+// mean of the whole sample, give or take the spread of a single word.
+func (s sampling) estimate(population, words []string, guesses []int) (float64, float64) {
+	sizes := map[string]int{}
+	keys := []string{}
+	for key, group := range strata(population, s.by) {
+		sizes[key] = len(group)
+		keys = append(keys, key)
+	}
+	sort.Strings(keys)
+
+	all := []float64{}
+	played := map[string][]float64{}
+	for i, word := range words {
+		key := stratum(word, s.by)
+		played[key] = append(played[key], float64(guesses[i]))
+		all = append(all, float64(guesses[i]))
+	}
+	overall, sd := meanStdDev(all)
+
+	// The spread within the groups that can measure it
+	pooled, df := 0.0, 0
+	for _, values := range played {
+		if len(values) > 1 {
+			_, sdh := meanStdDev(values)
+			pooled += float64(len(values)-1) * sdh * sdh
+			df += len(values) - 1
+		}
+	}
+	if df > 0 {
+		pooled /= float64(df)
+	} else {
+		pooled = sd * sd
+	}
+
+	mean, variance := 0.0, 0.0
+	for _, key := range keys {
+		size := sizes[key]
+		w := float64(size) / float64(len(population))
+		values, ok := played[key]
+		if !ok {
+			mean += w * overall
+			variance += w * w * sd * sd
+			continue
+		}
+
+		m, sdh := meanStdDev(values)
+		n := float64(len(values))
+		mean += w * m
+		if n == float64(size) {
+			continue
+		}
+		v := sdh * sdh
+		if len(values) == 1 {
+			v = pooled
+		}
+		variance += w * w * (1 - n/float64(size)) * v / n
+	}
+
+	return mean, 1.96 * math.Sqrt(variance)
+}
@@ -0,0 +1,141 @@
+package main
+
+import (
+	"math"
+	"reflect"
+	"sort"
+	"testing"
+)
+
+func TestStratum(t *testing.T) {
+	testCases := []struct {
+		word     string
+		by       string
+		expected string
+	}{
+		{"crane", "", ""},
+		{"crane", "first", "c"},
+		{"éclat", "first", "é"},
+		{"crane", "pattern", "ccvcv"},
+		{"gypsy", "pattern", "cvccv"},
+		{"über", "pattern", "vcvc"},
+	}
+
+	for _, testCase := range testCases {
+		answer := stratum(testCase.word, testCase.by)
+		if answer != testCase.expected {
+			t.Errorf("ERROR: For %s by %q expected %s, got %s", testCase.word, testCase.by, testCase.expected, answer)
+		}
+	}
+}
+
+func TestSamplingValid(t *testing.T) {
+	testCases := []struct {
+		s        sampling
+		expected bool
+	}{
+		{sampling{}, true},
+		{sampling{size: 10, by: "first"}, true},
+		{sampling{size: 10, by: "pattern"}, true},
+		{sampling{size: -1}, false},
+		{sampling{size: 1}, false},
+		{sampling{size: 2}, true},
+		{sampling{size: 10, by: "last"}, false},
+	}
+
+	for _, testCase := range testCases {
+		err := testCase.s.valid()
+		if (err == nil) != testCase.expected {
+			t.Errorf("ERROR: For %+v expected valid %t, got %v", testCase.s, testCase.expected, err)
+		}
+	}
+}
+
+func TestPick(t *testing.T) {
+	words := []string{"ant", "ape", "arc", "art", "bat", "bee", "cat", "cow", "dog", "dye"}
+
+	testCases := []struct {
+		s        sampling
+		expected map[string]int // how many words to expect from each group
+	}{
+		{sampling{size: 0, seed: 1}, map[string]int{"": 10}},
+		{sampling{size: 20, seed: 1}, map[string]int{"": 10}},
+		{sampling{size: 4, seed: 1}, map[string]int{"": 4}},
+		{sampling{size: 5, by: "first", seed: 1}, map[string]int{"a": 2, "b": 1, "c": 1, "d": 1}},
+		{sampling{size: 2, by: "first", seed: 7}, map[string]int{"a": 1, "b": 1, "c": 0, "d": 0}},
+		// Big enough for two words from every group
+		{sampling{size: 8, by: "first", seed: 1}, map[string]int{"a": 2, "b": 2, "c": 2, "d": 2}},
+		{sampling{size: 9, by: "first", seed: 1}, map[string]int{"a": 3, "b": 2, "c": 2, "d": 2}},
+	}
+
+	for _, testCase := range testCases {
+		picked := testCase.s.pick(words)
+		if !sort.StringsAreSorted(picked) {
+			t.Errorf("ERROR: For %+v expected sorted words, got %v", testCase.s, picked)
+		}
+		counts := map[string]int{}
+		for key := range testCase.expected {
+			counts[key] = 0
+		}
+		for _, word := range picked {
+			counts[stratum(word, testCase.s.by)]++
+		}
+		if !reflect.DeepEqual(counts, testCase.expected) {
+			t.Errorf("ERROR: For %+v expected %v, got %v from %v", testCase.s, testCase.expected, counts, picked)
+		}
+		if again := testCase.s.pick(words); !reflect.DeepEqual(again, picked) {
+			t.Errorf("ERROR: For %+v expected the same seed to pick %v, got %v", testCase.s, picked, again)
+		}
+	}
+}
+
+func TestEstimate(t *testing.T) {
+	population := []string{"ant", "ape", "arc", "bat", "bee", "bog"}
+
+	testCases := []struct {
+		s       sampling
+		words   []string
+		guesses []int
+		mean    float64
+		margin  float64
+	}{
+		// Every word was played, so there is no doubt about the mean
+		{sampling{}, population, []int{2, 3, 4, 5, 6, 7}, 4.5, 0},
+		{sampling{size: 2}, []string{"ant", "bat"}, []int{2, 4}, 3, 1.96 * math.Sqrt(2.0/3)},
+		// With one word from each group, their spread is that of the whole sample
+		{sampling{size: 2, by: "first"}, []string{"ant", "bat"}, []int{3, 5}, 4, 1.96 * math.Sqrt(2.0/3)},
+		// A group that was not sampled still counts, with the sample's mean
+		{sampling{size: 2, by: "first"}, []string{"ant", "arc"}, []int{3, 5}, 4, 1.96 * math.Sqrt(7.0/12)},
+		// A group with one word varies like the others
+		{sampling{size: 3, by: "first"}, []string{"ant", "arc", "bat"}, []int{3, 5, 6}, 5, 1.96 * math.Sqrt(5.0/12)},
+	}
+
+	for _, testCase := range testCases {
+		mean, margin := testCase.s.estimate(population, testCase.words, testCase.guesses)
+		if math.Abs(mean-testCase.mean) > 1e-9 || math.Abs(margin-testCase.margin) > 1e-9 {
+			t.Errorf("ERROR: For %+v %v expected %f ± %f, got %f ± %f", testCase.s, testCase.words, testCase.mean, testCase.margin, mean, margin)
+		}
+	}
+}
+
+func TestUnsampled(t *testing.T) {
+	population := []string{"ant", "ape", "bat", "cat"}
+
+	testCases := []struct {
+		s        sampling
+		words    []string
+		expected int
+	}{
+		{sampling{}, population, 0},
+		{sampling{size: 2}, []string{"ant", "bat"}, 0},
+		{sampling{size: 2, by: "first"}, []string{"ant", "bat"}, 1},
+		{sampling{size: 2, by: "first"}, []string{"ant", "ape"}, 2},
+	}
+
+	for _, testCase := range testCases {
+		answer := testCase.s.unsampled(population, testCase.words)
+		if answer != testCase.expected {
+			t.Errorf("ERROR: For %+v %v expected %d, got %d", testCase.s, testCase.words, testCase.expected, answer)
+		}
+	}
+}
@@ -0,0 +1,136 @@
+package main
+
+import (
+	"fmt"
+	"math"
+	"strconv"
+	"strings"
+	"time"
+
+	"github.com/erikbryant/dictionaries"
+)
+
+// The date of the first puzzle, number 0
+const firstPuzzle = "2021-06-19"
+
+// schedule maps puzzle numbers to their answers
+type schedule map[string]string
+
+// puzzleKey returns the puzzle number of a date or puzzle number, so either
+// can be used to look up a day. A number may have commas, as in a shared grid.
+// Any other id is returned unchanged; a # always starts a comment, so it is
+// never part of an id.
+func puzzleKey(id string) string {
+	if n, err := strconv.Atoi(strings.ReplaceAll(id, ",", "")); err == nil {
+		return strconv.Itoa(n)
+	}
+
+	date, err := time.Parse("2006-01-02", id)
+	if err != nil {
+		return id
+	}
+	first, _ := time.Parse("2006-01-02", firstPuzzle)
+
+	return strconv.Itoa(int(date.Sub(first).Hours() / 24))
+}
+
+// loadSchedule returns the schedule stored in file, in the same form as the
+// past answers file
+func loadSchedule(file string, alpha alphabet) (schedule, error) {
+	answers, err := loadAnswers(file, alpha)
+	if err != nil {
+		return nil, err
+	}
+	if answers == nil {
+		return nil, fmt.Errorf("schedule %s not found", file)
+	}
+
+	s := schedule{}
+	for _, a := range answers {
+		key := puzzleKey(a.day)
+		if word, ok := s[key]; ok && word != a.word {
+			return nil, fmt.Errorf("schedule %s has two answers for %s: %s and %s", file, a.day, word, a.word)
+		}
+		s[key] = a.word
+	}
+
+	return s, nil
+}
+
+// answer returns the scheduled answer for the day
+func (s schedule) answer(id string) (string, bool) {
+	word, ok := s[puzzleKey(id)]
+	return word, ok
+}
+
+// scheduleDays fills in the answer of each day from the schedule. It returns an
+// error if a day already has a different answer.
+func scheduleDays(days []puzzleDay, s schedule) error {
+	for i := range days {
+		word, ok := s.answer(days[i].id)
+		if !ok {
+			continue
+		}
+		if days[i].answer != "" && days[i].answer != word {
+			return fmt.Errorf("puzzle %s gives a different answer than the schedule", days[i].id)
+		}
+		days[i].answer = word
+	}
+
+	return nil
+}
+
+// bits returns how much information the colorbars gave, in bits
+func bits(pool, candidates int) float64 {
+	if pool == 0 || candidates == 0 {
+		return 0
+	}
+
+	return math.Log2(float64(pool) / float64(candidates))
+}
+
+// runVerify cracks every puzzle day in file and checks the candidates against
+// the scheduled answers, without printing the answers
+func runVerify(file, scheduleFile string, alpha alphabet, workers int) error {
+	s, err := loadSchedule(scheduleFile, alpha)
+	if err != nil {
+		return err
+	}
+
+	days, lists, err := loadBatch(file, alpha, s)
+	if err != nil {
+		return err
+	}
+
+	results := crackDays(days, lists, workers)
+	totalBits := 0.0
+	for _, r := range results {
+		fmt.Printf("%s: ", r.day.id)
+		if r.day.answer == "" {
+			fmt.Println("no scheduled answer")
+			continue
+		}
+		if r.err != nil {
+			fmt.Printf("answer MISSED, %v\n", r.err)
+			continue
+		}
+
+		length, _ := dayLength(r.day)
+		pool := len(lists[length].mysteries)
+		b := bits(pool, len(r.candidates))
+		if dictionaries.ContainsWord(r.candidates, r.day.answer) {
+			totalBits += b
+			fmt.Printf("answer found among %d of %d words (%.1f bits)\n", len(r.candidates), pool, b)
+		} else {
+			fmt.Printf("answer MISSED, %d of %d words left\n", len(r.candidates), pool)
+		}
+	}
+
+	stats := summarize(results)
+	printSummary(stats)
+	if stats.found > 0 {
+		fmt.Printf("Mean information found:   %.1f bits\n", totalBits/float64(stats.found))
+	}
+
+	return nil
+}
@@ -0,0 +1,101 @@
+package main
+
+import (
+	"math"
+	"os"
+	"path/filepath"
+	"testing"
+)
+
+func TestPuzzleKey(t *testing.T) {
+	testCases := []struct {
+		id       string
+		expected string
+	}{
+		{"0", "0"},
+		{"1234", "1234"},
+		{"1,234", "1234"},
+		{"#1234", "#1234"},
+		{"2021-06-19", "0"},
+		{"2021-06-20", "1"},
+		{"2024-05-01", "1047"},
+		{"yesterday", "yesterday"},
+	}
+
+	for _, testCase := range testCases {
+		answer := puzzleKey(testCase.id)
+		if answer != testCase.expected {
+			t.Errorf("ERROR: For %s expected %s, got %s", testCase.id, testCase.expected, answer)
+		}
+	}
+}
+
+func TestLoadSchedule(t *testing.T) {
+	dir := t.TempDir()
+	alpha := alphabets["en"]
+
+	_, err := loadSchedule(filepath.Join(dir, "missing"), alpha)
+	if err == nil {
+		t.Errorf("ERROR: expected an error for a missing schedule")
+	}
+
+	file := filepath.Join(dir, "schedule")
+	os.WriteFile(file, []byte("1047 moist\n2024-05-01 MOIST\n1000 crane\n"), 0644)
+	s, err := loadSchedule(file, alpha)
+	if err != nil {
+		t.Fatalf("ERROR: expected no error, got %v", err)
+	}
+	if word, ok := s.answer("2024-05-01"); !ok || word != "moist" {
+		t.Errorf("ERROR: expected moist, got %s %t", word, ok)
+	}
+	if _, ok := s.answer("1001"); ok {
+		t.Errorf("ERROR: expected no answer for 1001")
+	}
+
+	os.WriteFile(file, []byte("1047 moist\n2024-05-01 crane\n"), 0644)
+	_, err = loadSchedule(file, alpha)
+	if err == nil {
+		t.Errorf("ERROR: expected an error for two answers to one day")
+	}
+}
+
+func TestScheduleDays(t *testing.T) {
+	s := schedule{"1": "cat", "2": "dog"}
+
+	days := []puzzleDay{{id: "1"}, {id: "2", answer: "dog"}, {id: "3"}}
+	err := scheduleDays(days, s)
+	if err != nil || days[0].answer != "cat" || days[1].answer != "dog" || days[2].answer != "" {
+		t.Errorf("ERROR: expected cat dog and no answer, got %v %v", days, err)
+	}
+
+	days = []puzzleDay{{id: "1", answer: "cot"}}
+	if err := scheduleDays(days, s); err == nil {
+		t.Errorf("ERROR: expected an error for a conflicting answer")
+	}
+
+	days = []puzzleDay{{id: "1"}}
+	if err := scheduleDays(days, nil); err != nil || days[0].answer != "" {
+		t.Errorf("ERROR: expected a nil schedule to change nothing, got %v %v", days, err)
+	}
+}
+
+func TestBits(t *testing.T) {
+	testCases := []struct {
+		pool       int
+		candidates int
+		expected   float64
+	}{
+		{0, 0, 0},
+		{8, 0, 0},
+		{8, 8, 0},
+		{8, 1, 3},
+		{1024, 4, 8},
+	}
+
+	for _, testCase := range testCases {
+		answer := bits(testCase.pool, testCase.candidates)
+		if math.Abs(answer-testCase.expected) > 1e-9 {
+			t.Errorf("ERROR: For %d %d expected %f, got %f", testCase.pool, testCase.candidates, testCase.expected, answer)
+		}
+	}
+}
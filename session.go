@@ -0,0 +1,122 @@
+package main
+
+import (
+	"encoding/json"
+	"flag"
+	"fmt"
+	"os"
+	"strconv"
+	"strings"
+)
+
+// session records everything needed to get back to the same candidate words
+// later, possibly on another machine
+type session struct {
+	Dict       string   `json:"dict"`
+	DictHash   string   `json:"dictHash"`
+	Guessables string   `json:"guessables,omitempty"`
+	Lang       string   `json:"lang"`
+	Alphabet   string   `json:"alphabet,omitempty"`
+	Feedback   string   `json:"feedback"`
+	Strategy   string   `json:"strategy"`
+	Length     int      `json:"length"`
+	Relax      bool     `json:"relax"`
+	Excluded   []string `json:"excluded"` // past answers left out of the mystery words
+	Colorbars  []string `json:"colorbars"`
+	Guesses    []string `json:"guesses"`
+}
+
+// saveSession writes the session to file
+func saveSession(s session, file string) error {
+	raw, err := json.MarshalIndent(s, "", "  ")
+	if err != nil {
+		return err
+	}
+
+	return os.WriteFile(file, raw, 0644)
+}
+
+// loadSession returns the session stored in file
+func loadSession(file string) (session, error) {
+	s := session{}
+
+	raw, err := os.ReadFile(file)
+	if err != nil {
+		return s, err
+	}
+
+	err = json.Unmarshal(raw, &s)
+	if err != nil {
+		return s, fmt.Errorf("unable to parse session %s: %v", file, err)
+	}
+
+	return s, nil
+}
+
+// apply sets the flags in fs from the session. Flags given explicitly on the
+// command line take precedence, except colorbars and guesses given on the
+// command line, which are added to the session's.
+func (s session) apply(fs *flag.FlagSet) error {
+	set := map[string]bool{}
+	fs.Visit(func(f *flag.Flag) {
+		set[f.Name] = true
+	})
+
+	values := map[string]string{
+		"dict":       s.Dict,
+		"guessables": s.Guessables,
+		"lang":       s.Lang,
+		"alphabet":   s.Alphabet,
+		"feedback":   s.Feedback,
+		"strategy":   s.Strategy,
+		"length":     strconv.Itoa(s.Length),
+		"relax":      strconv.FormatBool(s.Relax),
+	}
+	for name, value := range values {
+		if set[name] || value == "" {
+			continue
+		}
+		if err := fs.Set(name, value); err != nil {
+			return err
+		}
+	}
+
+	appended := map[string][]string{
+		"colorbars": s.Colorbars,
+		"guessed":   s.Guesses,
+	}
+	for name, values := range appended {
+		if current := fs.Lookup(name).Value.String(); current != "" {
+			values = append(append([]string{}, values...), current)
+		}
+		if err := fs.Set(name, strings.Join(values, ",")); err != nil {
+			return err
+		}
+	}
+
+	return nil
+}
+
+// excluded returns the past answers the session left out of the mystery
+// words, or false if it was saved before they were recorded
+func (s session) excluded() ([]pastAnswer, bool) {
+	if s.Excluded == nil {
+		return nil, false
+	}
+
+	answers := []pastAnswer{}
+	for _, word := range s.Excluded {
+		answers = append(answers, pastAnswer{word: word})
+	}
+
+	return answers, true
+}
+
+// verify returns an error if the words are not the ones the session was saved with
+func (s session) verify(words []string) error {
+	if s.DictHash != "" && s.DictHash != dictHash(words) {
+		return fmt.Errorf("the dictionary %s has changed since the session was saved", s.Dict)
+	}
+
+	return nil
+}
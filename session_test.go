@@ -0,0 +1,141 @@
+package main
+
+import (
+	"flag"
+	"path/filepath"
+	"reflect"
+	"testing"
+)
+
+func TestSaveLoadSession(t *testing.T) {
+	file := filepath.Join(t.TempDir(), "session.json")
+	s := session{
+		Dict:      "test.dict",
+		DictHash:  dictHash([]string{"cat", "dog"}),
+		Lang:      "en",
+		Feedback:  "standard",
+		Strategy:  "letterFreq",
+		Length:    3,
+		Relax:     true,
+		Excluded:  []string{"cow"},
+		Colorbars: []string{"gbg"},
+		Guesses:   []string{"cat/gbg", "dog/bbb"},
+	}
+
+	err := saveSession(s, file)
+	if err != nil {
+		t.Fatalf("ERROR: unable to save session: %v", err)
+	}
+
+	answer, err := loadSession(file)
+	if err != nil {
+		t.Fatalf("ERROR: unable to load session: %v", err)
+	}
+	if answer.Dict != s.Dict || answer.DictHash != s.DictHash || answer.Length != s.Length ||
+		answer.Relax != s.Relax || !equal(answer.Excluded, s.Excluded) || !equal(answer.Colorbars, s.Colorbars) || !equal(answer.Guesses, s.Guesses) {
+		t.Errorf("ERROR: expected %v, got %v", s, answer)
+	}
+
+	_, err = loadSession(filepath.Join(t.TempDir(), "missing.json"))
+	if err == nil {
+		t.Errorf("ERROR: expected an error loading a missing session")
+	}
+}
+
+func TestSessionVerify(t *testing.T) {
+	words := []string{"cat", "dog"}
+
+	testCases := []struct {
+		s        session
+		words    []string
+		expected bool
+	}{
+		{session{}, words, true},
+		{session{DictHash: dictHash(words)}, words, true},
+		{session{DictHash: dictHash(words)}, []string{"cat", "cot"}, false},
+	}
+
+	for _, testCase := range testCases {
+		err := testCase.s.verify(testCase.words)
+		if (err == nil) != testCase.expected {
+			t.Errorf("ERROR: For %v expected ok=%t, got %v", testCase.words, testCase.expected, err)
+		}
+	}
+}
+
+func TestSessionApply(t *testing.T) {
+	s := session{
+		Dict:       "test.dict",
+		Guessables: "more.dict",
+		Lang:       "en",
+		Feedback:   "standard",
+		Strategy:   "letterFreq",
+		Length:     3,
+		Relax:      true,
+		Colorbars:  []string{"gbg"},
+		Guesses:    []string{"cat/gbg"},
+	}
+
+	testCases := []struct {
+		args     []string
+		expected map[string]string
+	}{
+		{
+			[]string{},
+			map[string]string{"dict": "test.dict", "guessables": "more.dict", "strategy": "letterFreq", "length": "3", "relax": "true", "colorbars": "gbg", "guessed": "cat/gbg"},
+		},
+		// The command line wins, but adds to the colorbars and guesses
+		{
+			[]string{"-strategy=minimax", "-length=5", "-relax=false", "-colorbars=yyy", "-guessed=dog/bbb"},
+			map[string]string{"dict": "test.dict", "strategy": "minimax", "length": "5", "relax": "false", "colorbars": "gbg,yyy", "guessed": "cat/gbg,dog/bbb"},
+		},
+	}
+
+	for _, testCase := range testCases {
+		fs := flag.NewFlagSet("test", flag.ContinueOnError)
+		for _, name := range []string{"dict", "guessables", "lang", "alphabet", "feedback", "strategy", "colorbars", "guessed"} {
+			fs.String(name, "", "")
+		}
+		fs.Int("length", 0, "")
+		fs.Bool("relax", false, "")
+		err := fs.Parse(testCase.args)
+		if err != nil {
+			t.Fatalf("ERROR: unable to parse %v: %v", testCase.args, err)
+		}
+
+		err = s.apply(fs)
+		if err != nil {
+			t.Errorf("ERROR: For %v expected no error, got %v", testCase.args, err)
+		}
+		for name, value := range testCase.expected {
+			if answer := fs.Lookup(name).Value.String(); answer != value {
+				t.Errorf("ERROR: For %v expected -%s=%s, got %s", testCase.args, name, value, answer)
+			}
+		}
+	}
+
+	fs := flag.NewFlagSet("test", flag.ContinueOnError)
+	fs.Int("length", 0, "")
+	if err := (session{Length: 3}).apply(fs); err == nil {
+		t.Errorf("ERROR: expected an error for a flag that does not exist")
+	}
+}
+
+func TestSessionExcluded(t *testing.T) {
+	testCases := []struct {
+		s        session
+		expected []pastAnswer
+		ok       bool
+	}{
+		{session{}, nil, false},
+		{session{Excluded: []string{}}, []pastAnswer{}, true},
+		{session{Excluded: []string{"cat", "dog"}}, []pastAnswer{{"", "cat"}, {"", "dog"}}, true},
+	}
+
+	for _, testCase := range testCases {
+		answer, ok := testCase.s.excluded()
+		if ok != testCase.ok || !reflect.DeepEqual(answer, testCase.expected) {
+			t.Errorf("ERROR: For %v expected %v %t, got %v %t", testCase.s.Excluded, testCase.expected, testCase.ok, answer, ok)
+		}
+	}
+}
@@ -0,0 +1,354 @@
+package main
+
+import (
+	"encoding/csv"
+	"encoding/json"
+	"fmt"
+	"io"
+	"math"
+	"os"
+	"sort"
+	"strconv"
+	"strings"
+)
+
+// The percentiles of the number of guesses reported by the benchmark
+var benchPercentiles = []int{50, 90, 95, 99}
+
+// How many of the hardest words the benchmark reports
+const hardestWords = 10
+
+// percentile is the number of guesses that p percent of games needed at most
+type percentile struct {
+	P       int `json:"p"`
+	Guesses int `json:"guesses"`
+}
+
+// hardWord is a mystery that took many guesses to find
+type hardWord struct {
+	Word    string `json:"word"`
+	Guesses int    `json:"guesses"`
+	Found   bool   `json:"found"`
+}
+
+// openerStats is how the games that began with one opening guess went
+type openerStats struct {
+	Opener   string  `json:"opener"`
+	Games    int     `json:"games"`
+	Mean     float64 `json:"mean"`
+	Failures int     `json:"failures"`
+}
+
+// benchStats summarizes the games played by the benchmark for one word length
+type benchStats struct {
+	Length       int           `json:"length"`
+	Words        int           `json:"words"`
+	Population   int           `json:"population"` // mystery words the games were sampled from
+	Mean         float64       `json:"mean"`
+	Estimate     float64       `json:"estimate"`  // estimated mean over the population
+	Margin       float64       `json:"margin"`    // half-width of the estimate's 95% confidence interval
+	Unsampled    int           `json:"unsampled"` // groups of the population with no word in the sample
+	Median       float64       `json:"median"`
+	StdDev       float64       `json:"stdDev"`
+	Percentiles  []percentile  `json:"percentiles"`
+	Distribution []int         `json:"distribution"` // games won in 1 through maxTurns guesses
+	Failures     int           `json:"failures"`     // games not won within maxTurns guesses
+	Hardest      []hardWord    `json:"hardest"`
+	Openers      []openerStats `json:"openers"`
+	Remaining    []float64     `json:"remaining"` // mean candidates left after each turn
+}
+
+// won returns true if the game found the mystery within maxTurns guesses
+func (g game) won() bool {
+	return g.found && len(g.turns) <= maxTurns
+}
+
+// newBenchStats returns the statistics of the games. Games that did not find
+// the mystery count the guesses they made.
+func newBenchStats(wordLen int, games []game) benchStats {
+	stats := benchStats{
+		Length:       wordLen,
+		Words:        len(games),
+		Population:   len(games),
+		Percentiles:  []percentile{},
+		Distribution: make([]int, maxTurns),
+		Hardest:      []hardWord{},
+		Openers:      []openerStats{},
+		Remaining:    []float64{},
+	}
+	if len(games) == 0 {
+		return stats
+	}
+
+	guesses := []int{}
+	values := []float64{}
+	for _, g := range games {
+		n := len(g.turns)
+		guesses = append(guesses, n)
+		values = append(values, float64(n))
+		if g.won() {
+			stats.Distribution[n-1]++
+		} else {
+			stats.Failures++
+		}
+	}
+	sort.Ints(guesses)
+
+	stats.Mean, stats.StdDev = meanStdDev(values)
+	stats.Estimate = stats.Mean
+	mid := len(guesses) / 2
+	stats.Median = float64(guesses[mid])
+	if len(guesses)%2 == 0 {
+		stats.Median = float64(guesses[mid-1]+guesses[mid]) / 2
+	}
+	for _, p := range benchPercentiles {
+		rank := int(math.Ceil(float64(p)/100*float64(len(guesses)))) - 1
+		stats.Percentiles = append(stats.Percentiles, percentile{p, guesses[max(rank, 0)]})
+	}
+
+	stats.Hardest = hardest(games, hardestWords)
+	stats.Openers = openers(games)
+	stats.Remaining = meanRemaining(games)
+
+	return stats
+}
+
+// meanStdDev returns the mean and standard deviation of the values
+func meanStdDev(values []float64) (float64, float64) {
+	if len(values) == 0 {
+		return 0, 0
+	}
+
+	mean := 0.0
+	for _, v := range values {
+		mean += v
+	}
+	mean /= float64(len(values))
+
+	variance := 0.0
+	for _, v := range values {
+		variance += (v - mean) * (v - mean)
+	}
+	if len(values) > 1 {
+		variance /= float64(len(values) - 1)
+	}
+
+	return mean, math.Sqrt(variance)
+}
+
+// hardest returns the n games that took the most guesses, those that never
+// found the mystery first
+func hardest(games []game, n int) []hardWord {
+	words := []hardWord{}
+	for _, g := range games {
+		words = append(words, hardWord{g.mystery, len(g.turns), g.found})
+	}
+
+	sort.Slice(words, func(i, j int) bool {
+		if words[i].Found != words[j].Found {
+			return !words[i].Found
+		}
+		if words[i].Guesses != words[j].Guesses {
+			return words[i].Guesses > words[j].Guesses
+		}
+		return words[i].Word < words[j].Word
+	})
+
+	return words[:min(n, len(words))]
+}
+
+// openers returns how the games went for each opening guess, the most used
+// opener first
+func openers(games []game) []openerStats {
+	byOpener := map[string]*openerStats{}
+	total := map[string]int{}
+
+	for _, g := range games {
+		if len(g.turns) == 0 {
+			continue
+		}
+		opener := g.turns[0].word
+		o, ok := byOpener[opener]
+		if !ok {
+			o = &openerStats{Opener: opener}
+			byOpener[opener] = o
+		}
+		o.Games++
+		total[opener] += len(g.turns)
+		if !g.won() {
+			o.Failures++
+		}
+	}
+
+	stats := []openerStats{}
+	for opener, o := range byOpener {
+		o.Mean = float64(total[opener]) / float64(o.Games)
+		stats = append(stats, *o)
+	}
+	sort.Slice(stats, func(i, j int) bool {
+		if stats[i].Games != stats[j].Games {
+			return stats[i].Games > stats[j].Games
+		}
+		return stats[i].Opener < stats[j].Opener
+	})
+
+	return stats
+}
+
+// meanRemaining returns the mean number of candidates left after each turn,
+// over the games that played that turn
+func meanRemaining(games []game) []float64 {
+	totals := []int{}
+	counts := []int{}
+
+	for _, g := range games {
+		for i, n := range g.remaining {
+			if i == len(totals) {
+				totals = append(totals, 0)
+				counts = append(counts, 0)
+			}
+			totals[i] += n
+			counts[i]++
+		}
+	}
+
+	means := []float64{}
+	for i := range totals {
+		means = append(means, float64(totals[i])/float64(counts[i]))
+	}
+
+	return means
+}
+
+// writeBenchStatsText writes the statistics in a form meant to be read
+func writeBenchStatsText(w io.Writer, stats benchStats) {
+	fmt.Fprintf(w, "\nWord length %d, %d words\n", stats.Length, stats.Words)
+	fmt.Fprintf(w, "Guesses: mean %.2f  median %.1f  std dev %.2f\n", stats.Mean, stats.Median, stats.StdDev)
+	if stats.Words < stats.Population {
+		fmt.Fprintf(w, "Estimated mean over all %d words: %.2f ± %.2f (95%% confidence)\n", stats.Population, stats.Estimate, stats.Margin)
+		if stats.Unsampled > 0 {
+			fmt.Fprintf(w, "Warning: %d groups of words were not sampled and are assumed to match the sample\n", stats.Unsampled)
+		}
+	}
+
+	ps := []string{}
+	for _, p := range stats.Percentiles {
+		ps = append(ps, fmt.Sprintf("p%d %d", p.P, p.Guesses))
+	}
+	fmt.Fprintf(w, "Percentiles: %s\n", strings.Join(ps, "  "))
+
+	fmt.Fprintln(w, "Distribution:")
+	for i, n := range stats.Distribution {
+		fmt.Fprintf(w, "  %d: %5d\n", i+1, n)
+	}
+	fmt.Fprintf(w, "  X: %5d\n", stats.Failures)
+
+	hard := []string{}
+	for _, h := range stats.Hardest {
+		if h.Found {
+			hard = append(hard, fmt.Sprintf("%s(%d)", h.Word, h.Guesses))
+		} else {
+			hard = append(hard, fmt.Sprintf("%s(not found)", h.Word))
+		}
+	}
+	fmt.Fprintf(w, "Hardest words: %s\n", strings.Join(hard, " "))
+
+	fmt.Fprintln(w, "Openers:")
+	for _, o := range stats.Openers {
+		fmt.Fprintf(w, "  %s  games %5d  mean %4.2f  failures %d\n", o.Opener, o.Games, o.Mean, o.Failures)
+	}
+
+	left := []string{}
+	for i, m := range stats.Remaining {
+		left = append(left, fmt.Sprintf("%d: %.1f", i+1, m))
+	}
+	fmt.Fprintf(w, "Mean candidates left after each turn: %s\n", strings.Join(left, "  "))
+}
+
+// printBenchStats prints the statistics
+func printBenchStats(stats benchStats) {
+	writeBenchStatsText(os.Stdout, stats)
+}
+
+// writeBenchStatsCSV writes the statistics as length,section,key,value rows
+func writeBenchStatsCSV(w io.Writer, all []benchStats) error {
+	c := csv.NewWriter(w)
+	c.Write([]string{"length", "section", "key", "value"})
+
+	for _, stats := range all {
+		row := func(section, key string, value any) {
+			c.Write([]string{strconv.Itoa(stats.Length), section, key, fmt.Sprint(value)})
+		}
+
+		row("summary", "words", stats.Words)
+		row("summary", "mean", strconv.FormatFloat(stats.Mean, 'f', 4, 64))
+		row("summary", "median", stats.Median)
+		row("summary", "stdDev", strconv.FormatFloat(stats.StdDev, 'f', 4, 64))
+		row("summary", "population", stats.Population)
+		row("summary", "estimate", strconv.FormatFloat(stats.Estimate, 'f', 4, 64))
+		row("summary", "margin", strconv.FormatFloat(stats.Margin, 'f', 4, 64))
+		row("summary", "unsampled", stats.Unsampled)
+		for _, p := range stats.Percentiles {
+			row("percentile", fmt.Sprintf("p%d", p.P), p.Guesses)
+		}
+		for i, n := range stats.Distribution {
+			row("distribution", strconv.Itoa(i+1), n)
+		}
+		row("distribution", "X", stats.Failures)
+		for _, h := range stats.Hardest {
+			if h.Found {
+				row("hardest", h.Word, h.Guesses)
+			} else {
+				row("hardest", h.Word, "X")
+			}
+		}
+		for _, o := range stats.Openers {
+			row("opener", o.Opener, strconv.FormatFloat(o.Mean, 'f', 4, 64))
+		}
+		for i, m := range stats.Remaining {
+			row("remaining", strconv.Itoa(i+1), strconv.FormatFloat(m, 'f', 4, 64))
+		}
+	}
+	c.Flush()
+
+	return c.Error()
+}
+
+// writeBenchStats writes the statistics of every word length to w in the
+// given format: text, csv, or json
+func writeBenchStats(w io.Writer, all []benchStats, format string) error {
+	switch format {
+	case "text":
+		for _, stats := range all {
+			writeBenchStatsText(w, stats)
+		}
+		return nil
+	case "csv":
+		return writeBenchStatsCSV(w, all)
+	case "json":
+		raw, err := json.MarshalIndent(all, "", "  ")
+		if err != nil {
+			return err
+		}
+		_, err = fmt.Fprintln(w, string(raw))
+		return err
+	}
+
+	return fmt.Errorf("unknown format %s", format)
+}
+
+// saveBenchStats writes the statistics to file in the given format
+func saveBenchStats(all []benchStats, file, format string) error {
+	f, err := os.Create(file)
+	if err != nil {
+		return err
+	}
+
+	err = writeBenchStats(f, all, format)
+	if err != nil {
+		f.Close()
+		return err
+	}
+
+	return f.Close()
+}
@@ -0,0 +1,148 @@
+package main
+
+import (
+	"bytes"
+	"encoding/json"
+	"math"
+	"strings"
+	"testing"
+)
+
+// testGame returns a game that took the given guesses, all after opener
+func testGame(mystery, opener string, guesses int, found bool) game {
+	g := game{mystery: mystery, turns: history{}, remaining: []int{}, found: found}
+
+	for i := 0; i < guesses; i++ {
+		word := opener
+		if i > 0 {
+			word = "xxx"
+		}
+		g.turns = append(g.turns, turn{word, "bbb"})
+		g.remaining = append(g.remaining, guesses-i)
+	}
+
+	return g
+}
+
+func TestMeanStdDev(t *testing.T) {
+	testCases := []struct {
+		values []float64
+		mean   float64
+		sd     float64
+	}{
+		{[]float64{}, 0, 0},
+		{[]float64{5}, 5, 0},
+		{[]float64{1, 3}, 2, math.Sqrt2},
+		{[]float64{2, 4, 4, 4, 5, 5, 7, 9}, 5, math.Sqrt(32.0 / 7)},
+	}
+
+	for _, testCase := range testCases {
+		mean, sd := meanStdDev(testCase.values)
+		if math.Abs(mean-testCase.mean) > 1e-9 || math.Abs(sd-testCase.sd) > 1e-9 {
+			t.Errorf("ERROR: For %v expected %f %f, got %f %f", testCase.values, testCase.mean, testCase.sd, mean, sd)
+		}
+	}
+}
+
+func TestNewBenchStats(t *testing.T) {
+	games := []game{
+		testGame("cat", "tag", 1, true),
+		testGame("cot", "tag", 3, true),
+		testGame("dog", "tag", 3, true),
+		testGame("act", "dog", 7, true),
+		testGame("zzz", "dog", 2, false),
+	}
+
+	stats := newBenchStats(3, games)
+
+	if stats.Length != 3 || stats.Words != 5 || stats.Mean != 16.0/5 || stats.Median != 3 {
+		t.Errorf("ERROR: expected length 3, 5 words, mean 3.2, median 3, got %+v", stats)
+	}
+	// The same sample standard deviation as the tournament reports
+	if _, sd := meanStdDev([]float64{1, 3, 3, 7, 2}); stats.StdDev != sd || sd < 2.28 || sd > 2.29 {
+		t.Errorf("ERROR: expected std dev 2.28, got %f", stats.StdDev)
+	}
+	expectedP := []percentile{{50, 3}, {90, 7}, {95, 7}, {99, 7}}
+	for i, p := range expectedP {
+		if stats.Percentiles[i] != p {
+			t.Errorf("ERROR: expected percentile %v, got %v", p, stats.Percentiles[i])
+		}
+	}
+	expectedD := []int{1, 0, 2, 0, 0, 0}
+	for i, n := range expectedD {
+		if stats.Distribution[i] != n {
+			t.Errorf("ERROR: expected distribution %v, got %v", expectedD, stats.Distribution)
+			break
+		}
+	}
+	if stats.Failures != 2 {
+		t.Errorf("ERROR: expected 2 failures, got %d", stats.Failures)
+	}
+
+	hardestWords := []string{}
+	for _, h := range stats.Hardest {
+		hardestWords = append(hardestWords, h.Word)
+	}
+	if !equal(hardestWords, []string{"zzz", "act", "cot", "dog", "cat"}) {
+		t.Errorf("ERROR: expected zzz act cot dog cat hardest first, got %v", hardestWords)
+	}
+
+	if len(stats.Openers) != 2 || stats.Openers[0] != (openerStats{"tag", 3, 7.0 / 3, 0}) || stats.Openers[1] != (openerStats{"dog", 2, 4.5, 2}) {
+		t.Errorf("ERROR: expected tag then dog openers, got %v", stats.Openers)
+	}
+
+	// After turn 1 the games have 1, 3, 3, 7 and 2 left
+	if len(stats.Remaining) != 7 || stats.Remaining[0] != 16.0/5 || stats.Remaining[6] != 1 {
+		t.Errorf("ERROR: expected 7 turns from 3.2 down to 1, got %v", stats.Remaining)
+	}
+
+	empty := newBenchStats(5, []game{})
+	if empty.Words != 0 || empty.Failures != 0 || len(empty.Distribution) != maxTurns {
+		t.Errorf("ERROR: expected empty statistics, got %+v", empty)
+	}
+}
+
+func TestPlayGame(t *testing.T) {
+	suggest := strategies["letterFreq"]
+
+	g := playGame("tag", consistencyTestWords, suggest, nil)
+	if !g.found || g.turns[len(g.turns)-1].word != "tag" || len(g.remaining) != len(g.turns) || g.remaining[len(g.remaining)-1] != 1 {
+		t.Errorf("ERROR: expected to find tag, got %+v", g)
+	}
+
+	g = playGame("zzz", consistencyTestWords, suggest, nil)
+	if g.found {
+		t.Errorf("ERROR: expected not to find zzz, got %+v", g)
+	}
+}
+
+func TestWriteBenchStats(t *testing.T) {
+	all := []benchStats{newBenchStats(3, []game{testGame("cat", "tag", 2, true)})}
+
+	var b bytes.Buffer
+	err := writeBenchStats(&b, all, "csv")
+	if err != nil || !strings.HasPrefix(b.String(), "length,section,key,value\n3,summary,words,1\n3,summary,mean,2.0000\n") ||
+		!strings.Contains(b.String(), "3,distribution,2,1\n") || !strings.Contains(b.String(), "3,opener,tag,2.0000\n") {
+		t.Errorf("ERROR: unexpected csv %q %v", b.String(), err)
+	}
+
+	b.Reset()
+	err = writeBenchStats(&b, all, "json")
+	decoded := []benchStats{}
+	if err == nil {
+		err = json.Unmarshal(b.Bytes(), &decoded)
+	}
+	if err != nil || len(decoded) != 1 || decoded[0].Mean != 2 || decoded[0].Hardest[0].Word != "cat" {
+		t.Errorf("ERROR: expected json to round trip, got %v %v", decoded, err)
+	}
+
+	b.Reset()
+	err = writeBenchStats(&b, all, "text")
+	if err != nil || !strings.Contains(b.String(), "Guesses: mean 2.00") {
+		t.Errorf("ERROR: unexpected text %q %v", b.String(), err)
+	}
+
+	if err := writeBenchStats(&b, all, "xml"); err == nil {
+		t.Errorf("ERROR: expected an error for an unknown format")
+	}
+}
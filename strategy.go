@@ -0,0 +1,205 @@
+package main
+
+import (
+	"strconv"
+	"strings"
+	"sync"
+	"unicode/utf8"
+
+	"github.com/erikbryant/dictionaries"
+)
+
+// preferCandidates breaks ties between equally good guesses in favor of those
+// that could be the answer; otherwise the first in dictionary order wins
+var preferCandidates = true
+
+// probes holds, by word length, the words that the minimax and expected
+// strategies may guess even though they cannot be the answer. It is empty
+// unless they are allowed to.
+var probes = map[int][]string{}
+
+// How many guesses partitionGuess remembers before it starts over
+const maxPartitions = 100000
+
+// partitions remembers the guesses partitionGuess has chosen. A benchmark
+// reaches the same candidates over and over, and the first guess alone means
+// comparing every word with every other.
+var partitions = struct {
+	sync.Mutex
+	guesses map[string]string
+}{guesses: map[string]string{}}
+
+// scoreWordPositional returns the sum of unique letter frequencies for a given
+// word plus how often each of its letters appears in that position. A repeated
+// letter only earns its positional frequency, since it cannot reveal a new
+// letter but can still turn green.
+func scoreWordPositional(word string, lFreq map[rune]int, lByPos []map[rune]int) int {
+	used := map[rune]bool{}
+	score := 0
+
+	for i, val := range []rune(word) {
+		if i < len(lByPos) {
+			score += lByPos[i][val]
+		}
+		if used[val] {
+			continue
+		}
+		score += lFreq[val]
+		used[val] = true
+	}
+
+	return score
+}
+
+// suggestGuessPositional suggests the match whose letters are most common, both
+// anywhere in the matches and in the positions it puts them
+func suggestGuessPositional(matches []string, h history) string {
+	lFreq, lByPos := letterFrequency(matches)
+
+	scores := make([]score, len(matches))
+	for i, word := range matches {
+		scores[i] = score{scoreWordPositional(word, lFreq, lByPos), word}
+	}
+
+	return findMaxScore(scores, h).word
+}
+
+// loadProbes returns the words of every length in the alphabet's dictionary
+// and its guessable words, by length, for the minimax and expected strategies
+// to probe with
+func loadProbes(alpha alphabet) (map[int][]string, error) {
+	words, err := alpha.words()
+	if err != nil {
+		return nil, err
+	}
+	if alpha.guesses != "" {
+		more, err := alpha.load(alpha.guesses)
+		if err != nil {
+			return nil, err
+		}
+		words = append(words, more...)
+	}
+
+	byLength := map[int][]string{}
+	for _, word := range dictionaries.SortUnique(words) {
+		if word != "" && alpha.valid(word) {
+			n := utf8.RuneCountInString(word)
+			byLength[n] = append(byLength[n], word)
+		}
+	}
+
+	return byLength, nil
+}
+
+// bucketSizes returns how many of the matches give each colorbar for guess,
+// leaving out the guess itself since guessing it ends the game
+func bucketSizes(matches []string, guess string) map[string]int {
+	sizes := map[string]int{}
+
+	for _, word := range matches {
+		if word != guess {
+			sizes[makeMask(word, guess)]++
+		}
+	}
+
+	return sizes
+}
+
+// largestBucket returns the size of the largest bucket
+func largestBucket(sizes map[string]int) int {
+	largest := 0
+
+	for _, n := range sizes {
+		largest = max(largest, n)
+	}
+
+	return largest
+}
+
+// sumOfSquares returns the sum of the squares of the bucket sizes. Divided by
+// the number of matches it is the expected number of candidates left.
+func sumOfSquares(sizes map[string]int) int {
+	sum := 0
+
+	for _, n := range sizes {
+		sum += n * n
+	}
+
+	return sum
+}
+
+// partitionGuess returns the guess not yet made whose colorbars split the
+// matches into buckets with the lowest cost. The matches are always possible
+// guesses, as are any probes of the same length.
+func partitionGuess(name string, matches []string, h history, cost func(sizes map[string]int) int) string {
+	if len(matches) == 0 {
+		return ""
+	}
+	pool := probes[utf8.RuneCountInString(matches[0])]
+
+	guessed := []string{}
+	for _, t := range h {
+		guessed = append(guessed, t.word)
+	}
+	key := strings.Join([]string{name, feedback.name(), dictHash(matches), strings.Join(guessed, ","), strconv.FormatBool(preferCandidates), dictHash(pool)}, "/")
+	partitions.Lock()
+	guess, ok := partitions.guesses[key]
+	partitions.Unlock()
+	if ok {
+		return guess
+	}
+
+	candidate := map[string]bool{}
+	for _, word := range matches {
+		candidate[word] = true
+	}
+
+	best := -1
+	consider := func(word string) {
+		if h.guessed(word) {
+			return
+		}
+		c := cost(bucketSizes(matches, word))
+		switch {
+		case best < 0 || c < best:
+		case c > best:
+			return
+		case preferCandidates && candidate[word] != candidate[guess]:
+			if !candidate[word] {
+				return
+			}
+		case word > guess:
+			return
+		}
+		best, guess = c, word
+	}
+	for _, word := range matches {
+		consider(word)
+	}
+	for _, word := range pool {
+		if !candidate[word] {
+			consider(word)
+		}
+	}
+
+	partitions.Lock()
+	if len(partitions.guesses) >= maxPartitions {
+		partitions.guesses = map[string]string{}
+	}
+	partitions.guesses[key] = guess
+	partitions.Unlock()
+
+	return guess
+}
+
+// suggestGuessMinimax suggests the guess that leaves the fewest candidates in
+// the worst case
+func suggestGuessMinimax(matches []string, h history) string {
+	return partitionGuess("minimax", matches, h, largestBucket)
+}
+
+// suggestGuessExpected suggests the guess that leaves the fewest candidates on
+// average, taking every match to be equally likely
+func suggestGuessExpected(matches []string, h history) string {
+	return partitionGuess("expected", matches, h, sumOfSquares)
+}
@@ -0,0 +1,147 @@
+package main
+
+import (
+	"reflect"
+	"testing"
+)
+
+func TestScoreWordPositional(t *testing.T) {
+	lFreq, lByPos := letterFrequency([]string{"cat", "cot", "tat"})
+
+	testCases := []struct {
+		w        string
+		expected int
+	}{
+		{"", 0},
+		{"cat", 15},
+		{"cot", 13},
+		{"tat", 12},
+		{"act", 11},
+		{"ttt", 8},
+	}
+
+	for _, testCase := range testCases {
+		answer := scoreWordPositional(testCase.w, lFreq, lByPos)
+		if answer != testCase.expected {
+			t.Errorf("ERROR: For %s expected %d, got %d", testCase.w, testCase.expected, answer)
+		}
+	}
+}
+
+func TestSuggestGuessPositional(t *testing.T) {
+	testCases := []struct {
+		m        []string
+		g        history
+		expected string
+	}{
+		{[]string{""}, guessedWords(), ""},
+		{[]string{"abc"}, guessedWords(), "abc"},
+		{[]string{"abc"}, guessedWords("abc"), ""},
+		{[]string{"cat", "cot", "tat"}, guessedWords(), "cat"},
+		{[]string{"cat", "cot", "tat"}, guessedWords("cat"), "cot"},
+		// Overall frequency ties these; cba has each letter in its most common position
+		{[]string{"abc", "bca", "cab", "cba"}, guessedWords(), "cba"},
+	}
+
+	for _, testCase := range testCases {
+		answer := suggestGuessPositional(testCase.m, testCase.g)
+		if answer != testCase.expected {
+			t.Errorf("ERROR: For %v %s expected %s, got %s", testCase.m, testCase.g, testCase.expected, answer)
+		}
+	}
+}
+
+func TestBucketSizes(t *testing.T) {
+	testCases := []struct {
+		m        []string
+		g        string
+		expected map[string]int
+		largest  int
+		squares  int
+	}{
+		{[]string{}, "cat", map[string]int{}, 0, 0},
+		{[]string{"cat"}, "cat", map[string]int{}, 0, 0},
+		{[]string{"bat", "cat", "cot", "hat"}, "cat", map[string]int{"bgg": 2, "gbg": 1}, 2, 5},
+		{[]string{"bat", "cat", "hat"}, "bch", map[string]int{"gbb": 1, "byb": 1, "bby": 1}, 1, 3},
+	}
+
+	for _, testCase := range testCases {
+		answer := bucketSizes(testCase.m, testCase.g)
+		if !reflect.DeepEqual(answer, testCase.expected) {
+			t.Errorf("ERROR: For %v %s expected %v, got %v", testCase.m, testCase.g, testCase.expected, answer)
+		}
+		if largest := largestBucket(answer); largest != testCase.largest {
+			t.Errorf("ERROR: For %v %s expected largest %d, got %d", testCase.m, testCase.g, testCase.largest, largest)
+		}
+		if squares := sumOfSquares(answer); squares != testCase.squares {
+			t.Errorf("ERROR: For %v %s expected sum of squares %d, got %d", testCase.m, testCase.g, testCase.squares, squares)
+		}
+	}
+}
+
+func TestPartitionGuess(t *testing.T) {
+	defer func() {
+		probes = map[int][]string{}
+		preferCandidates = true
+	}()
+
+	testCases := []struct {
+		s        string
+		m        []string
+		g        history
+		probes   []string
+		prefer   bool
+		expected string
+	}{
+		{"minimax", []string{}, guessedWords(), nil, true, ""},
+		{"expected", []string{"abc"}, guessedWords("abc"), nil, true, ""},
+		{"minimax", []string{"bat", "cat", "cot", "hat"}, guessedWords(), nil, true, "bat"},
+		{"expected", []string{"bat", "cat", "cot", "hat"}, guessedWords(), nil, true, "bat"},
+		{"expected", []string{"bat", "cat", "cot", "hat"}, guessedWords("bat"), nil, true, "cat"},
+		// A word that cannot be the answer may split the matches best
+		{"minimax", []string{"bat", "cat", "hat"}, guessedWords(), []string{"bch"}, true, "bch"},
+		{"expected", []string{"bat", "cat", "hat"}, guessedWords(), []string{"bch"}, true, "bch"},
+		// Another probe list of the same size must not reuse the guess above
+		{"expected", []string{"bat", "cat", "hat"}, guessedWords(), []string{"xyz"}, true, "bat"},
+		// Ties go to a word that could be the answer, unless told otherwise
+		{"minimax", []string{"bat", "cat"}, guessedWords(), []string{"abc"}, true, "bat"},
+		{"minimax", []string{"bat", "cat"}, guessedWords(), []string{"abc"}, false, "abc"},
+		// Guessing a match might end the game, which is worth more on average
+		{"expected", []string{"bat", "cat"}, guessedWords(), []string{"abc"}, false, "bat"},
+	}
+
+	for _, testCase := range testCases {
+		probes = map[int][]string{3: testCase.probes}
+		preferCandidates = testCase.prefer
+		answer := strategies[testCase.s](testCase.m, testCase.g)
+		if answer != testCase.expected {
+			t.Errorf("ERROR: For %s %v %s %v expected %s, got %s", testCase.s, testCase.m, testCase.g, testCase.probes, testCase.expected, answer)
+		}
+	}
+}
+
+func TestLoadProbes(t *testing.T) {
+	es := alphabets["es"]
+	es.dict = "testdata/es.dict"
+
+	byLength, err := loadProbes(es)
+	if err != nil || !equal(byLength[4], []string{"gato", "niño", "papa"}) || len(byLength[3]) != 1 {
+		t.Errorf("ERROR: expected probes by length, got %v %v", byLength, err)
+	}
+
+	es.guesses = "testdata/es_guesses.dict"
+	byLength, err = loadProbes(es)
+	if err != nil || !equal(byLength[4], []string{"gata", "gato", "mesa", "niño", "papa"}) {
+		t.Errorf("ERROR: expected probes to include the guessables, got %v %v", byLength, err)
+	}
+
+	es.guesses = "testdata/missing.dict"
+	if _, err := loadProbes(es); err == nil {
+		t.Errorf("ERROR: expected an error for missing guessables")
+	}
+
+	es.dict = "testdata/missing.dict"
+	if _, err := loadProbes(es); err == nil {
+		t.Errorf("ERROR: expected an error for a missing dictionary")
+	}
+}
@@ -0,0 +1,302 @@
+package main
+
+import (
+	"encoding/csv"
+	"fmt"
+	"math"
+	"os"
+	"sort"
+	"strconv"
+	"strings"
+)
+
+// How many of the words with the biggest differences a tournament reports
+const biggestDifferences = 10
+
+// tournament is the games each strategy played over the same mystery words
+type tournament struct {
+	strategies []string
+	population []string // every mystery word
+	sample     sampling
+	mysteries  []string // the mystery words played
+	games      [][]game // games[i][j] is strategy i playing mystery j
+}
+
+// playTournament plays every strategy over the same sample of the mystery
+// words
+func playTournament(population []string, names []string, book *openingBook, workers int, s sampling) tournament {
+	mysteries := s.pick(population)
+	t := tournament{strategies: names, population: population, sample: s, mysteries: mysteries, games: [][]game{}}
+
+	for _, name := range names {
+		suggest := strategies[name]
+		b := book
+		if !b.covers(population, name) {
+			b = nil
+		}
+
+		games := make([]game, len(mysteries))
+		parallel(len(mysteries), workers, func(i int) {
+			games[i] = playGame(mysteries[i], population, suggest, b)
+		})
+		t.games = append(t.games, games)
+	}
+
+	return t
+}
+
+// guesses returns how many guesses strategy i made for each mystery
+func (t tournament) guesses(i int) []int {
+	counts := []int{}
+
+	for _, g := range t.games[i] {
+		counts = append(counts, len(g.turns))
+	}
+
+	return counts
+}
+
+// failures returns how many games strategy i did not win
+func (t tournament) failures(i int) int {
+	n := 0
+
+	for _, g := range t.games[i] {
+		if !g.won() {
+			n++
+		}
+	}
+
+	return n
+}
+
+// pairedTest runs a paired t-test on b - a. It returns the mean difference,
+// the t statistic, the degrees of freedom and the two-sided p-value.
+func pairedTest(a, b []int) (float64, float64, int, float64) {
+	diffs := []float64{}
+	for i := range a {
+		diffs = append(diffs, float64(b[i]-a[i]))
+	}
+
+	mean, sd := meanStdDev(diffs)
+	df := len(diffs) - 1
+	if df < 1 {
+		return mean, 0, df, 1
+	}
+	if sd == 0 {
+		if mean == 0 {
+			return mean, 0, df, 1
+		}
+		return mean, math.Inf(int(math.Copysign(1, mean))), df, 0
+	}
+
+	t := mean / (sd / math.Sqrt(float64(len(diffs))))
+
+	return mean, t, df, studentTwoSided(t, df)
+}
+
+// studentTwoSided returns the probability of a Student's t statistic at least
+// as far from zero as t, with df degrees of freedom
+func studentTwoSided(t float64, df int) float64 {
+	v := float64(df)
+	return betaInc(v/2, 0.5, v/(v+t*t))
+}
+
+// betaInc returns the regularized incomplete beta function I_x(a, b)
+func betaInc(a, b, x float64) float64 {
+	if x <= 0 {
+		return 0
+	}
+	if x >= 1 {
+		return 1
+	}
+
+	// The continued fraction converges quickly on this side; use the symmetry
+	// I_x(a, b) = 1 - I_(1-x)(b, a) on the other
+	if x > (a+1)/(a+b+2) {
+		return 1 - betaInc(b, a, 1-x)
+	}
+
+	lgab, _ := math.Lgamma(a + b)
+	lga, _ := math.Lgamma(a)
+	lgb, _ := math.Lgamma(b)
+	front := math.Exp(lgab - lga - lgb + a*math.Log(x) + b*math.Log(1-x))
+
+	return front * betaFraction(a, b, x) / a
+}
+
+// betaFraction evaluates the continued fraction for the incomplete beta
+// function by the modified Lentz method
+func betaFraction(a, b, x float64) float64 {
+	const tiny = 1e-300
+
+	c, d := 1.0, 1-(a+b)*x/(a+1)
+	if math.Abs(d) < tiny {
+		d = tiny
+	}
+	d = 1 / d
+	f := d
+
+	for m := 1; m <= 300; m++ {
+		fm := float64(m)
+		for _, num := range []float64{
+			fm * (b - fm) * x / ((a + 2*fm - 1) * (a + 2*fm)),
+			-(a + fm) * (a + b + fm) * x / ((a + 2*fm) * (a + 2*fm + 1)),
+		} {
+			d = 1 + num*d
+			if math.Abs(d) < tiny {
+				d = tiny
+			}
+			c = 1 + num/c
+			if math.Abs(c) < tiny {
+				c = tiny
+			}
+			d = 1 / d
+			f *= d * c
+		}
+		if math.Abs(d*c-1) < 1e-15 {
+			break
+		}
+	}
+
+	return f
+}
+
+// printTournament prints each strategy's results side by side, then compares
+// every other strategy with the first
+func printTournament(t tournament) {
+	if t.sample.sampled(t.population) {
+		fmt.Printf("\nPlayed %d of %d mystery words, seed %d\n", len(t.mysteries), len(t.population), t.sample.seed)
+		if n := t.sample.unsampled(t.population, t.mysteries); n > 0 {
+			fmt.Printf("Warning: %d groups of words were not sampled and are assumed to match the sample\n", n)
+		}
+	}
+	fmt.Printf("\n%-14s %6s %7s %7s %6s\n", "Strategy", "Mean", "±95%", "StdDev", "Fail%")
+	for i, name := range t.strategies {
+		values := []float64{}
+		for _, n := range t.guesses(i) {
+			values = append(values, float64(n))
+		}
+		_, sd := meanStdDev(values)
+		mean, margin := t.sample.estimate(t.population, t.mysteries, t.guesses(i))
+		fmt.Printf("%-14s %6.3f %7.3f %7.3f %5.1f%%\n", name, mean, margin, sd, percent(t.failures(i), len(t.mysteries)))
+	}
+
+	base := t.guesses(0)
+	for i := 1; i < len(t.strategies); i++ {
+		other := t.guesses(i)
+		better, worse := 0, 0
+		for j := range base {
+			switch {
+			case other[j] < base[j]:
+				better++
+			case other[j] > base[j]:
+				worse++
+			}
+		}
+
+		mean, tStat, df, p := pairedTest(base, other)
+		fmt.Printf("\n%s vs %s: mean difference %+.3f guesses (better on %d words, worse on %d, same on %d)\n",
+			t.strategies[i], t.strategies[0], mean, better, worse, len(base)-better-worse)
+		significant := "not significant"
+		if p < 0.05 {
+			significant = "significant"
+		}
+		fmt.Printf("  paired t = %.3f, df = %d, p = %.4f (%s at 5%%)\n", tStat, df, p, significant)
+
+		diffs := []string{}
+		for _, j := range biggestDiffs(base, other, biggestDifferences) {
+			diffs = append(diffs, fmt.Sprintf("%s %+d", t.mysteries[j], other[j]-base[j]))
+		}
+		if len(diffs) > 0 {
+			fmt.Printf("  biggest differences: %s\n", strings.Join(diffs, ", "))
+		}
+	}
+}
+
+// biggestDiffs returns the indexes of the n words whose guesses differ the
+// most between a and b, ignoring words with no difference
+func biggestDiffs(a, b []int, n int) []int {
+	indexes := []int{}
+	for i := range a {
+		if a[i] != b[i] {
+			indexes = append(indexes, i)
+		}
+	}
+
+	abs := func(i int) int {
+		if b[i] > a[i] {
+			return b[i] - a[i]
+		}
+		return a[i] - b[i]
+	}
+	sort.SliceStable(indexes, func(i, j int) bool {
+		return abs(indexes[i]) > abs(indexes[j])
+	})
+
+	return indexes[:min(n, len(indexes))]
+}
+
+// saveTournamentCSV writes one row per mystery word with each strategy's
+// guesses, and whether it won
+func saveTournamentCSV(t tournament, file string) error {
+	f, err := os.Create(file)
+	if err != nil {
+		return err
+	}
+
+	c := csv.NewWriter(f)
+	header := []string{"word"}
+	for _, name := range t.strategies {
+		header = append(header, name, name+"_won")
+	}
+	c.Write(header)
+	for j, mystery := range t.mysteries {
+		row := []string{mystery}
+		for i := range t.strategies {
+			g := t.games[i][j]
+			row = append(row, strconv.Itoa(len(g.turns)), strconv.FormatBool(g.won()))
+		}
+		c.Write(row)
+	}
+	c.Flush()
+
+	if err := c.Error(); err != nil {
+		f.Close()
+		return err
+	}
+
+	return f.Close()
+}
+
+// runTournament plays the named strategies over the same sample of mystery
+// words and prints how they compare. If csvFile is set the per-word results
+// are also written there.
+func runTournament(wordLen int, alpha alphabet, names []string, book *openingBook, workers int, s sampling, csvFile string) error {
+	if len(names) < 2 {
+		return fmt.Errorf("a tournament needs at least two strategies, got %v", names)
+	}
+	seen := map[string]bool{}
+	for _, name := range names {
+		if _, ok := strategies[name]; !ok {
+			return fmt.Errorf("unknown strategy %s", name)
+		}
+		if seen[name] {
+			return fmt.Errorf("strategy %s is in the tournament twice", name)
+		}
+		seen[name] = true
+	}
+
+	mysteries, _, err := loadDicts(wordLen, alpha)
+	if err != nil {
+		return err
+	}
+
+	t := playTournament(mysteries, names, book, workers, s)
+	printTournament(t)
+
+	if csvFile == "" {
+		return nil
+	}
+
+	return saveTournamentCSV(t, csvFile)
+}
@@ -0,0 +1,145 @@
+package main
+
+import (
+	"math"
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+)
+
+func TestStudentTwoSided(t *testing.T) {
+	testCases := []struct {
+		t        float64
+		df       int
+		expected float64
+	}{
+		{0, 10, 1},
+		{2.228, 10, 0.05},
+		{-2.228, 10, 0.05},
+		{12.706, 1, 0.05},
+		{3.169, 10, 0.01},
+		{1.960, 100000, 0.05},
+		{1, 1, 0.5},
+	}
+
+	for _, testCase := range testCases {
+		answer := studentTwoSided(testCase.t, testCase.df)
+		if math.Abs(answer-testCase.expected) > 0.0005 {
+			t.Errorf("ERROR: For t=%f df=%d expected %f, got %f", testCase.t, testCase.df, testCase.expected, answer)
+		}
+	}
+}
+
+func TestPairedTest(t *testing.T) {
+	testCases := []struct {
+		a    []int
+		b    []int
+		mean float64
+		t    float64
+		p    float64
+	}{
+		{[]int{3, 4, 5}, []int{3, 4, 5}, 0, 0, 1},
+		{[]int{3}, []int{4}, 1, 0, 1},
+		{[]int{3, 4, 5}, []int{4, 5, 6}, 1, math.Inf(1), 0},
+		{[]int{3, 4, 5, 6}, []int{4, 4, 6, 6}, 0.5, 1.732, 0.182},
+	}
+
+	for _, testCase := range testCases {
+		mean, tStat, _, p := pairedTest(testCase.a, testCase.b)
+		if math.Abs(mean-testCase.mean) > 1e-9 || (math.Abs(tStat-testCase.t) > 0.001 && tStat != testCase.t) || math.Abs(p-testCase.p) > 0.001 {
+			t.Errorf("ERROR: For %v %v expected %f %f %f, got %f %f %f", testCase.a, testCase.b, testCase.mean, testCase.t, testCase.p, mean, tStat, p)
+		}
+	}
+}
+
+func TestBiggestDiffs(t *testing.T) {
+	testCases := []struct {
+		a        []int
+		b        []int
+		n        int
+		expected []int
+	}{
+		{[]int{1, 2, 3}, []int{1, 2, 3}, 10, []int{}},
+		{[]int{1, 2, 3}, []int{4, 2, 1}, 10, []int{0, 2}},
+		{[]int{1, 2, 3}, []int{4, 2, 1}, 1, []int{0}},
+		{[]int{5, 2, 3}, []int{4, 2, 9}, 10, []int{2, 0}},
+	}
+
+	for _, testCase := range testCases {
+		answer := biggestDiffs(testCase.a, testCase.b, testCase.n)
+		if !equalInts(answer, testCase.expected) {
+			t.Errorf("ERROR: For %v %v expected %v, got %v", testCase.a, testCase.b, testCase.expected, answer)
+		}
+	}
+}
+
+func TestPlayTournament(t *testing.T) {
+	// A strategy that always guesses the first word left
+	strategies["first"] = func(matches []string, h history) string {
+		for _, word := range matches {
+			if !h.guessed(word) {
+				return word
+			}
+		}
+		return ""
+	}
+	defer delete(strategies, "first")
+
+	tour := playTournament(consistencyTestWords, []string{"letterFreq", "first"}, nil, 2, sampling{})
+	if len(tour.games) != 2 || len(tour.games[1]) != len(consistencyTestWords) {
+		t.Fatalf("ERROR: expected 2 strategies of %d games, got %v", len(consistencyTestWords), tour.games)
+	}
+	for i, word := range consistencyTestWords {
+		for s := range tour.strategies {
+			if g := tour.games[s][i]; !g.won() || g.mystery != word {
+				t.Errorf("ERROR: For %s expected %s to win, got %+v", tour.strategies[s], word, g)
+			}
+		}
+	}
+	if g := tour.guesses(1); g[0] != 1 {
+		t.Errorf("ERROR: expected first to find cat in one guess, got %v", g)
+	}
+	if tour.failures(0) != 0 {
+		t.Errorf("ERROR: expected no failures, got %d", tour.failures(0))
+	}
+
+	file := filepath.Join(t.TempDir(), "tournament.csv")
+	err := saveTournamentCSV(tour, file)
+	raw, _ := os.ReadFile(file)
+	if err != nil || !strings.HasPrefix(string(raw), "word,letterFreq,letterFreq_won,first,first_won\ncat,") {
+		t.Errorf("ERROR: unexpected csv %q %v", raw, err)
+	}
+}
+
+func TestPlayTournamentBook(t *testing.T) {
+	book, err := makeBook(consistencyTestWords, "letterFreq")
+	if err != nil {
+		t.Fatalf("ERROR: unable to make book: %v", err)
+	}
+	// An opener the strategy would never pick shows whether the book is used
+	book.Opener = "dog"
+	book.Second = map[string]string{}
+
+	tour := playTournament(consistencyTestWords, []string{"letterFreq"}, &book, 2, sampling{})
+	for _, g := range tour.games[0] {
+		if g.turns[0].word != "dog" {
+			t.Errorf("ERROR: For %s expected the book's opener dog, got %s", g.mystery, g.turns[0].word)
+		}
+	}
+}
+
+func TestRunTournamentErrors(t *testing.T) {
+	testCases := [][]string{
+		{"letterFreq"},
+		{"letterFreq", "nobody"},
+		{"letterFreq", "letterFreq"},
+	}
+
+	for _, testCase := range testCases {
+		err := runTournament(5, alphabets["en"], testCase, nil, 1, sampling{}, "")
+		if err == nil {
+			t.Errorf("ERROR: For %v expected an error, got nil", testCase)
+		}
+	}
+}